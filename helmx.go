@@ -26,10 +26,18 @@ func NewHelmX() *HelmX {
 type HelmX struct {
     spec.Spec
     *tmpl.TemplateMgr
+    // working dir of git repository for version from git, current dir as default
+    Dir string
 }
 
 func (hx *HelmX) FromYAML(data []byte) error {
-    return yaml.Unmarshal(data, &hx.Spec)
+    if err := yaml.Unmarshal(data, &hx.Spec); err != nil {
+        return err
+    }
+    if hx.Spec.Project != nil {
+        return hx.Spec.Project.ResolveVersion(hx.Dir)
+    }
+    return nil
 }
 
 func (hx *HelmX) ToYAML() ([]byte, error) {
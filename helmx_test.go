@@ -45,7 +45,7 @@ service:
     - "data:/usr/share/nginx:ro"
   ports:
     - "80:80"
-    - "!20000:80"
+    - "!20000:8080"
   livenessProbe:
     action: "http://:80"
   lifecycle:
@@ -162,9 +162,15 @@ apiVersion: v1
 kind: Service
 metadata:
   name: helmx--test
-  annotations: 
-    helmx/project: >-
-      {"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    helmx/project: '{"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}'
     helmx/upstreams: ""
 spec:
   selector:
@@ -173,7 +179,7 @@ spec:
   ports:
   - name: http-80
     port: 80
-    targetPort: 80
+    targetPort: http-80
     protocol: TCP
 `,
         )
@@ -193,9 +199,15 @@ apiVersion: v1
 kind: Service
 metadata:
   name: helmx--test
-  annotations: 
-    helmx/project: >-
-      {"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    helmx/project: '{"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}'
     helmx/upstreams: ""
 spec:
   selector:
@@ -205,21 +217,64 @@ spec:
   ports:
   - name: http-80
     port: 80
-    targetPort: 80
+    targetPort: http-80
     protocol: TCP
 `,
         )
     })
+    t.Run("service with app protocol", func(t *testing.T) {
+        check(t, baseProject+`
+service:
+  ports:
+    - "grpc-9000:9090"
+    - "websockets-10080"
+`,
+            service,
+            `
+--- 
+
+apiVersion: v1
+kind: Service
+metadata:
+  name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    helmx/project: '{"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}'
+    helmx/upstreams: ""
+spec:
+  selector:
+    srv: helmx--test
+  type: ClusterIP
+  ports:
+  - name: grpc-9000
+    port: 9000
+    targetPort: grpc-9090
+    appProtocol: grpc
+    protocol: TCP
+  - name: websockets-10080
+    port: 10080
+    targetPort: 10080
+    appProtocol: websockets
+    protocol: TCP
+`,
+        )
+    })
+
     t.Run("service with nodePort", func(t *testing.T) {
         check(t, baseProject+`
 service:
   ports:
     - "!20000:80"
-    - "!80"
+    - "!8080"
     - "!25000:25000"
-    - "!40000:80"
-    - "80:8080"
-	- "80"
+    - "!40000:9090"
+    - "81:8081"
 `,
             service,
             `
@@ -229,9 +284,15 @@ apiVersion: v1
 kind: Service
 metadata:
   name: helmx--test
-  annotations: 
-    helmx/project: >-
-      {"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    helmx/project: '{"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}'
     helmx/upstreams: ""
 spec:
   selector:
@@ -241,30 +302,212 @@ spec:
   - name: np-http-20000
     nodePort: 20000
     port: 80
-    targetPort: 80
+    targetPort: http-80
     protocol: TCP
-  - name: np-http-80
-    port: 80
-    targetPort: 80
+  - name: http-8080
+    port: 8080
+    targetPort: http-8080
     protocol: TCP
   - name: np-http-25000
     nodePort: 25000
     port: 25000
-    targetPort: 25000
+    targetPort: http-25000
     protocol: TCP
   - name: np-http-40000
     nodePort: 40000
-    port: 80
-    targetPort: 80
+    port: 9090
+    targetPort: http-9090
     protocol: TCP
-  - name: http-80
+  - name: http-81
+    port: 81
+    targetPort: http-8081
+    protocol: TCP
+`,
+        )
+    })
+
+    t.Run("service with load balancer", func(t *testing.T) {
+        check(t, baseProject+`
+service:
+  ports:
+    - "^443:8443"
+    - "^!30080:80"
+  loadBalancerSourceRanges:
+    - 10.0.0.0/8
+  externalTrafficPolicy: Local
+  internalTrafficPolicy: Cluster
+  sessionAffinity: ClientIP
+  ipFamilyPolicy: PreferDualStack
+`,
+            service,
+            `
+--- 
+
+apiVersion: v1
+kind: Service
+metadata:
+  name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    helmx/project: '{"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}'
+    helmx/upstreams: ""
+spec:
+  selector:
+    srv: helmx--test
+  type: LoadBalancer
+  ports:
+  - name: http-443
+    port: 443
+    targetPort: http-8443
+    protocol: TCP
+  - name: np-http-30080
+    nodePort: 30080
     port: 80
-    targetPort: 8080
+    targetPort: http-80
     protocol: TCP
+  loadBalancerSourceRanges:
+  - 10.0.0.0/8
+  externalTrafficPolicy: Local
+  internalTrafficPolicy: Cluster
+  sessionAffinity: ClientIP
+  ipFamilyPolicy: PreferDualStack
+`,
+        )
+    })
+
+    t.Run("namespace", func(t *testing.T) {
+        check(t, baseProject+`
+namespace: helmx?create&podSecurity=restricted
+`,
+            namespace,
+            `
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: helmx
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+    pod-security.kubernetes.io/audit: restricted
+    pod-security.kubernetes.io/enforce: restricted
+    pod-security.kubernetes.io/warn: restricted
+`,
+        )
+    })
+
+    t.Run("service with namespace of target", func(t *testing.T) {
+        check(t, baseProject+`
+namespace: helmx
+target:
+  namespace: helmx-staging
+service:
+  ports:
+    - "80"
+  serviceAccountName: helmx
+  serviceAccountRoleRules:
+    - secrets#get
+`,
+            service+serviceAccount,
+            `
+--- 
+
+apiVersion: v1
+kind: Service
+metadata:
+  name: helmx--test
+  namespace: helmx-staging
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    helmx/project: '{"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"}'
+    helmx/upstreams: ""
+spec:
+  selector:
+    srv: helmx--test
+  type: ClusterIP
+  ports:
   - name: http-80
     port: 80
-    targetPort: 80
+    targetPort: http-80
     protocol: TCP
+
+
+
+
+--- 
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: helmx
+  namespace: helmx-staging
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+rules:
+  - apiGroups:
+    - ""
+    resources:
+    - secrets
+    verbs:
+    - get
+
+---
+
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: helmx
+  namespace: helmx-staging
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: helmx
+  namespace: helmx-staging
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+subjects:
+  - kind: ServiceAccount
+    name: helmx
+    namespace: helmx-staging
+    apiGroup: rbac.authorization.k8s.io
+roleRef:
+  kind: Role
+  name: helmx
+  apiGroup: rbac.authorization.k8s.io
 `,
         )
     })
@@ -272,8 +515,10 @@ spec:
     t.Run("ingress with tls", func(t *testing.T) {
         check(t, baseProject+`
 service:
+  ingressClassName: nginx
   ingresses:
     - "http://helmx:80/helmx"
+    - "http://helmx:80?pathType=Exact"
   tls:
     - "secretName:helmx"
 `,
@@ -281,12 +526,71 @@ service:
             `
 --- 
 
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: helmx
+    http:
+      paths:
+      - path: /helmx
+        pathType: Prefix
+        backend:
+          service:
+            name: helmx--test
+            port:
+              number: 80
+      - path: /
+        pathType: Exact
+        backend:
+          service:
+            name: helmx--test
+            port:
+              number: 80
+  tls:
+  - hosts:
+    - helmx
+    secretName: secretName
+`,
+        )
+    })
+
+    t.Run("ingress for legacy target", func(t *testing.T) {
+        check(t, baseProject+`
+target:
+  ingressVersion: extensions/v1beta1
+service:
+  ingressClassName: nginx
+  ingresses:
+    - "http://helmx:80/helmx"
+`,
+            ingress,
+            `
+--- 
+
 apiVersion: extensions/v1beta1
 kind: Ingress
 metadata:
   name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
   annotations:
-    kubernetes.io/ingress.class: "nginx"
+    kubernetes.io/ingress.class: nginx
 spec:
   rules:
   - host: helmx
@@ -296,9 +600,464 @@ spec:
         backend:
           serviceName: helmx--test
           servicePort: 80
+`,
+        )
+    })
+
+    t.Run("ingress with backends", func(t *testing.T) {
+        check(t, baseProject+`
+service:
+  ingressDefaultBackend: "default-http-backend:80"
+  ingresses:
+    - "http://api.helmx/static -> static-files:8080"
+    - "http://api.helmx:80/api"
+    - "http://*.helmx/"
+    - "http://*/healthz -> :8080"
+`,
+            ingress,
+            `
+--- 
+
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+spec:
+  defaultBackend:
+    service:
+      name: default-http-backend
+      port:
+        number: 80
+  rules:
+  - host: api.helmx
+    http:
+      paths:
+      - path: /static
+        pathType: Prefix
+        backend:
+          service:
+            name: static-files
+            port:
+              number: 8080
+      - path: /api
+        pathType: Prefix
+        backend:
+          service:
+            name: helmx--test
+            port:
+              number: 80
+  - host: '*.helmx'
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: helmx--test
+            port:
+              number: 80
+  - http:
+      paths:
+      - path: /healthz
+        pathType: Prefix
+        backend:
+          service:
+            name: helmx--test
+            port:
+              number: 8080
+`,
+        )
+    })
+
+    t.Run("http routes", func(t *testing.T) {
+        check(t, baseProject+`
+target:
+  gateway: gateway-system/public?http=web&https=websecure
+service:
+  ingresses:
+    - "http://helmx/api?rewrite=/"
+    - "http://helmx/static?pathType=Exact -> static-files:8080"
+    - "http://*/healthz"
   tls:
-  - host:
-    - helmx
+    - "secretName:helmx"
+`,
+            httpRoute,
+            `
+--- 
+
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+spec:
+  parentRefs:
+  - namespace: gateway-system
+    name: public
+    sectionName: websecure
+  hostnames:
+  - helmx
+  rules:
+  - matches:
+    - path:
+        type: PathPrefix
+        value: /api
+    filters:
+    - type: URLRewrite
+      urlRewrite:
+        path:
+          type: ReplacePrefixMatch
+          replacePrefixMatch: /
+    backendRefs:
+    - name: helmx--test
+      port: 80
+  - matches:
+    - path:
+        type: Exact
+        value: /static
+    backendRefs:
+    - name: static-files
+      port: 8080
+
+--- 
+
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: helmx--test--1
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+spec:
+  parentRefs:
+  - namespace: gateway-system
+    name: public
+    sectionName: web
+  rules:
+  - matches:
+    - path:
+        type: PathPrefix
+        value: /healthz
+    backendRefs:
+    - name: helmx--test
+      port: 80
+`,
+        )
+    })
+
+    t.Run("network policy", func(t *testing.T) {
+        check(t, baseProject+`
+service:
+  ports:
+    - "80:8080"
+    - "53/udp"
+upstreams:
+  - redis
+  - mysql.db
+  - api.example.com
+networkPolicy:
+  egressCIDRs:
+    - "0.0.0.0/0!10.0.0.0/8"
+`,
+            networkPolicy,
+            `
+--- 
+
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+spec:
+  podSelector:
+    matchLabels:
+      srv: helmx--test
+  policyTypes:
+  - Ingress
+  - Egress
+  ingress:
+  - ports:
+    - protocol: TCP
+      port: 8080
+    - protocol: UDP
+      port: 53
+  egress:
+  - ports:
+    - protocol: UDP
+      port: 53
+    - protocol: TCP
+      port: 53
+  - to:
+    - podSelector:
+        matchLabels:
+          srv: redis
+    - podSelector:
+        matchLabels:
+          srv: mysql
+      namespaceSelector:
+        matchLabels:
+          kubernetes.io/metadata.name: db
+  - to:
+    - ipBlock:
+        cidr: 0.0.0.0/0
+        except:
+        - 10.0.0.0/8
+`,
+        )
+    })
+
+    t.Run("external services", func(t *testing.T) {
+        check(t, baseProject+`
+upstreams:
+  - redis
+  - db=postgres.internal.example.com:5432
+  - cache=10.0.0.10:6379
+`,
+            externalServices,
+            `
+--- 
+
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+spec:
+  type: ExternalName
+  ports:
+  - name: tcp-5432
+    port: 5432
+    protocol: TCP
+  externalName: postgres.internal.example.com
+
+--- 
+
+apiVersion: v1
+kind: Service
+metadata:
+  name: cache
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+spec:
+  type: ClusterIP
+  ports:
+  - name: tcp-6379
+    port: 6379
+    protocol: TCP
+
+--- 
+
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+metadata:
+  name: cache
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+    kubernetes.io/service-name: cache
+addressType: IPv4
+endpoints:
+- addresses:
+  - 10.0.0.10
+ports:
+- name: tcp-6379
+  port: 6379
+  protocol: TCP
+`,
+        )
+    })
+
+    t.Run("ingress with port name", func(t *testing.T) {
+        check(t, baseProject+`
+service:
+  ports:
+    - "grpc-9000"
+  ingressClassName: nginx
+  ingresses:
+    - "http://grpc.helmx:grpc-9000"
+`,
+            ingress,
+            `
+--- 
+
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    nginx.ingress.kubernetes.io/backend-protocol: GRPC
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: grpc.helmx
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: helmx--test
+            port:
+              name: grpc-9000
+`,
+        )
+    })
+
+    t.Run("ingresses with controller profile", func(t *testing.T) {
+        check(t, baseProject+`
+service:
+  ports:
+    - "80:80"
+    - "grpc-9000:9000"
+  ingressClassName: nginx
+  ingress:
+    bodySize: 8m
+  ingresses:
+    - "http://helmx:80/helmx"
+    - "http://helmx:80/api?rewrite=/&annotation=nginx.ingress.kubernetes.io/ssl-redirect=false"
+    - "http://grpc.helmx:9000"
+  tls:
+    - "secretName:grpc.helmx"
+`,
+            ingress,
+            `
+--- 
+
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: helmx--test
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    nginx.ingress.kubernetes.io/proxy-body-size: 8m
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: helmx
+    http:
+      paths:
+      - path: /helmx
+        pathType: Prefix
+        backend:
+          service:
+            name: helmx--test
+            port:
+              number: 80
+
+--- 
+
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: helmx--test--1
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    nginx.ingress.kubernetes.io/proxy-body-size: 8m
+    nginx.ingress.kubernetes.io/rewrite-target: /
+    nginx.ingress.kubernetes.io/ssl-redirect: "false"
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: helmx
+    http:
+      paths:
+      - path: /api
+        pathType: Prefix
+        backend:
+          service:
+            name: helmx--test
+            port:
+              number: 80
+
+--- 
+
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: helmx--test--2
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+  annotations:
+    nginx.ingress.kubernetes.io/backend-protocol: GRPC
+    nginx.ingress.kubernetes.io/proxy-body-size: 8m
+spec:
+  ingressClassName: nginx
+  rules:
+  - host: grpc.helmx
+    http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: helmx--test
+            port:
+              number: 9000
+  tls:
+  - hosts:
+    - grpc.helmx
     secretName: secretName
 `,
         )
@@ -335,10 +1094,14 @@ kind: Deployment
 metadata:
   name: helmx--test
   labels:
-    app: helmx--test
-    version: 0.0.0
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
   annotations:
-    helmx: "{\"project\":{\"name\":\"helmx\",\"feature\":\"test\",\"version\":\"0.0.0\",\"group\":\"helmx\",\"description\":\"helmx\"},\"service\":{\"securityContext\":{\"runAsUser\":1024,\"runAsGroup\":1000,\"runAsNonRoot\":true,\"readOnlyRootFilesystem\":true,\"privileged\":true},\"hostNetwork\":true,\"hosts\":[\"127.0.0.1:test1.com,test2.com\",\"127.0.0.2:test3.com,test4.com\"],\"ports\":[\"80\"]},\"envs\":{\"configMap\":\"####configMapName.configMapKey####\",\"env\":\"test\",\"secretFalse\":\"####secretName.secretKey.false####\",\"secretTrue\":\"####secretName.secretKey.true####\",\"valueWithDot\":\"value.with.dot\"}}"
+    helmx: '{"project":{"name":"helmx","feature":"test","version":"0.0.0","group":"helmx","description":"helmx"},"service":{"securityContext":{"runAsUser":1024,"runAsGroup":1000,"runAsNonRoot":true,"readOnlyRootFilesystem":true,"privileged":true},"hostNetwork":true,"hosts":["127.0.0.1:test1.com,test2.com","127.0.0.2:test3.com,test4.com"],"ports":["80"]},"envs":{"configMap":"####configMapName.configMapKey####","env":"test","secretFalse":"####secretName.secretKey.false####","secretTrue":"####secretName.secretKey.true####","valueWithDot":"value.with.dot"}}'
 spec:
   selector:
     matchLabels:
@@ -346,6 +1109,12 @@ spec:
   template:
     metadata:
       labels:
+        app.kubernetes.io/component: test
+        app.kubernetes.io/instance: helmx--test
+        app.kubernetes.io/managed-by: helmx
+        app.kubernetes.io/name: helmx
+        app.kubernetes.io/part-of: helmx
+        app.kubernetes.io/version: 0.0.0
         srv: helmx--test
     spec:
       containers:
@@ -358,7 +1127,8 @@ spec:
           privileged: true
         image: docker.io/pf-helmx/helmx:0.0.0
         ports:
-        - containerPort: 80
+        - name: http-80
+          containerPort: 80
           protocol: TCP
         env:
         - name: configMap
@@ -419,13 +1189,149 @@ apiVersion: batch/v1
 kind: Job
 metadata:
   name: helmx--test--doonce
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
 spec:
   backoffLimit: 4
   template:
+    metadata:
+      labels:
+        app.kubernetes.io/component: test
+        app.kubernetes.io/instance: helmx--test
+        app.kubernetes.io/managed-by: helmx
+        app.kubernetes.io/name: helmx
+        app.kubernetes.io/part-of: helmx
+        app.kubernetes.io/version: 0.0.0
+    spec:
+      containers:
+      - name: helmx--test
+        image: busybox
+        imagePullPolicy: Always
+      imagePullSecrets:
+      - name: qcloud-registry
+      restartPolicy: Never
+`,
+        )
+    })
+
+    t.Run("job with labels and annotations", func(t *testing.T) {
+        check(t, baseProject+`
+labels:
+  team: infra
+annotations:
+  owner: infra@helmx.io
+podAnnotations:
+  prometheus.io/scrape: "true"
+metadata:
+  job:
+    annotations:
+      owner: jobs@helmx.io
+  pod:
+    labels:
+      sidecar.istio.io/inject: "false"
+jobs:
+  doonce:
+    image: busybox
+    restartPolicy: Never
+`,
+            job,
+            `
+---
+
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: helmx--test--doonce
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+    team: infra
+  annotations:
+    owner: jobs@helmx.io
+spec:
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/component: test
+        app.kubernetes.io/instance: helmx--test
+        app.kubernetes.io/managed-by: helmx
+        app.kubernetes.io/name: helmx
+        app.kubernetes.io/part-of: helmx
+        app.kubernetes.io/version: 0.0.0
+        sidecar.istio.io/inject: "false"
+        team: infra
+      annotations:
+        prometheus.io/scrape: "true"
+    spec:
+      containers:
+      - name: helmx--test
+        image: busybox
+        imagePullPolicy: Always
+      imagePullSecrets:
+      - name: qcloud-registry
+      restartPolicy: Never
+`,
+        )
+    })
+
+    t.Run("job with resources", func(t *testing.T) {
+        check(t, baseProject+`
+resources:
+  cpu: 1/2
+  memory: 1Gi/4Gi
+jobs:
+  migrate:
+    image: busybox
+    restartPolicy: Never
+    resources:
+      memory: 64Mi/128Mi
+`,
+            job,
+            `
+---
+
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: helmx--test--migrate
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
+spec:
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/component: test
+        app.kubernetes.io/instance: helmx--test
+        app.kubernetes.io/managed-by: helmx
+        app.kubernetes.io/name: helmx
+        app.kubernetes.io/part-of: helmx
+        app.kubernetes.io/version: 0.0.0
     spec:
       containers:
       - name: helmx--test
+        resources:
+          requests:
+            cpu: "1"
+            memory: 64Mi
+          limits:
+            cpu: "2"
+            memory: 128Mi
         image: busybox
+        imagePullPolicy: Always
       imagePullSecrets:
       - name: qcloud-registry
       restartPolicy: Never
@@ -454,15 +1360,39 @@ apiVersion: batch/v1beta1
 kind: CronJob
 metadata:
   name: helmx--test--docron
+  labels:
+    app.kubernetes.io/component: test
+    app.kubernetes.io/instance: helmx--test
+    app.kubernetes.io/managed-by: helmx
+    app.kubernetes.io/name: helmx
+    app.kubernetes.io/part-of: helmx
+    app.kubernetes.io/version: 0.0.0
 spec:
   schedule: '*/1 * * * *'
   jobTemplate:
+    metadata:
+      labels:
+        app.kubernetes.io/component: test
+        app.kubernetes.io/instance: helmx--test
+        app.kubernetes.io/managed-by: helmx
+        app.kubernetes.io/name: helmx
+        app.kubernetes.io/part-of: helmx
+        app.kubernetes.io/version: 0.0.0
     spec:
       template:
+        metadata:
+          labels:
+            app.kubernetes.io/component: test
+            app.kubernetes.io/instance: helmx--test
+            app.kubernetes.io/managed-by: helmx
+            app.kubernetes.io/name: helmx
+            app.kubernetes.io/part-of: helmx
+            app.kubernetes.io/version: 0.0.0
         spec:
           containers:
           - name: helmx--test
             image: busybox
+            imagePullPolicy: Always
           imagePullSecrets:
           - name: qcloud-registry
           restartPolicy: Never
@@ -479,11 +1409,10 @@ var (
 apiVersion: v1
 kind: Service
 metadata:
-  name: {{ ( .Project.FullName ) }}
-  annotations: 
-    helmx/project: >-
-      {{ toJson .Project }}
-    helmx/upstreams: {{ join .Upstreams "," | quote }}
+{{- $meta := toKubeObjectMeta . "service" .Project.FullName }}
+{{- $meta = $meta.WithAnnotation "helmx/project" ( toJson .Project ) }}
+{{- $meta = $meta.WithAnnotation "helmx/upstreams" ( join .Upstreams "," ) }}
+{{ spaces 2 | toYamlIndent $meta }}
 spec:
   selector:
     srv: {{ ( .Project.FullName ) }}
@@ -497,12 +1426,9 @@ spec:
 apiVersion: apps/v1
 kind: Deployment
 metadata:
-  name: {{ ( .Project.FullName ) }}
-  labels:
-    app: {{ ( .Project.FullName ) }}
-    version: {{ ( .Project.Version ) }}
-  annotations:
-    helmx: {{ toJson . | quote }}
+{{- $meta := toKubeObjectMeta . "deployment" .Project.FullName }}
+{{- $meta = $meta.WithAnnotation "helmx" ( toJson . ) }}
+{{ spaces 2 | toYamlIndent $meta }}
 spec:
   selector:
     matchLabels:
@@ -519,7 +1445,7 @@ spec:
 apiVersion: batch/v1
 kind: Job
 metadata:
-  name: {{ ( $spec.Project.FullName ) }}--{{ $name }}
+{{ spaces 2 | toYamlIndent ( toKubeObjectMeta $spec "job" ( toKubeName $spec.Project.FullName $name ) ) }}
 spec:
 {{ spaces 2 | toYamlIndent ( toKubeJobSpec $spec $job )  }}
 {{ end }}
@@ -535,7 +1461,7 @@ spec:
 apiVersion: batch/v1beta1
 kind: CronJob
 metadata:
-  name: {{ ( $spec.Project.FullName ) }}--{{ $name }}
+{{ spaces 2 | toYamlIndent ( toKubeObjectMeta $spec "cronJob" ( toKubeNameWithMaxLength 52 $spec.Project.FullName $name ) ) }}
 spec:
 {{ spaces 2 | toYamlIndent ( toKubeCronJobSpec $spec $job )  }}
 {{ end }}
@@ -544,16 +1470,60 @@ spec:
 
     ingress = `
 {{ if ( len .Service.Ingresses ) }}
+{{ range ( toKubeIngresses . ) }}
 --- 
 
-apiVersion: extensions/v1beta1
+apiVersion: {{ $.Target.IngressAPIVersion }}
 kind: Ingress
 metadata:
-  name: {{ ( .Project.FullName ) }}
-  annotations:
-    kubernetes.io/ingress.class: "nginx"
+{{ spaces 2 | toYamlIndent .Metadata }}
+spec:
+{{ spaces 2 | toYamlIndent .Spec }}
+{{ end }}
+{{ end }}
+`
+    httpRoute = `
+{{ range ( toKubeHTTPRoutes . ) }}
+--- 
+
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+{{ spaces 2 | toYamlIndent .Metadata }}
+spec:
+{{ spaces 2 | toYamlIndent .Spec }}
+{{ end }}
+`
+    networkPolicy = `
+{{ if .NetworkPolicy }}
+--- 
+
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+{{ spaces 2 | toYamlIndent ( toKubeObjectMeta . "networkPolicy" .Project.FullName ) }}
+spec:
+{{ spaces 2 | toYamlIndent ( toKubeNetworkPolicySpec . ) }}
+{{ end }}
+`
+    externalServices = `
+{{ range ( toKubeExternalServices . ) }}
+--- 
+
+apiVersion: v1
+kind: Service
+metadata:
+{{ spaces 2 | toYamlIndent .Metadata }}
 spec:
-{{ spaces 2 | toYamlIndent ( toKubeIngressSpec . )}}
+{{ spaces 2 | toYamlIndent .Spec }}
+{{- if .EndpointSlice }}
+
+--- 
+
+apiVersion: discovery.k8s.io/v1
+kind: EndpointSlice
+{{ spaces 0 | toYamlIndent .EndpointSlice }}
+{{ end }}
 {{ end }}
 `
     serviceAccount = `
@@ -563,7 +1533,7 @@ spec:
 apiVersion: rbac.authorization.k8s.io/v1
 kind: Role
 metadata:
-  name: {{ ( .Service.ServiceAccountName ) }}
+{{ spaces 2 | toYamlIndent ( toKubeObjectMeta . "role" .Service.ServiceAccountName ) }}
 rules:
 {{ spaces 2 | toYamlIndent ( toKubeRoleRules . )}}
 
@@ -572,22 +1542,35 @@ rules:
 apiVersion: v1
 kind: ServiceAccount
 metadata:
-  name: {{ ( .Service.ServiceAccountName ) }}
+{{ spaces 2 | toYamlIndent ( toKubeObjectMeta . "serviceAccount" .Service.ServiceAccountName ) }}
 
 ---
 apiVersion: rbac.authorization.k8s.io/v1
 kind: RoleBinding
 metadata:
-  name: {{ ( .Service.ServiceAccountName ) }}
+{{ spaces 2 | toYamlIndent ( toKubeObjectMeta . "roleBinding" .Service.ServiceAccountName ) }}
 subjects:
   - kind: ServiceAccount
     name: {{ ( .Service.ServiceAccountName ) }}
+{{- with .ResolveNamespace }}
+    namespace: {{ . }}
+{{- end }}
     apiGroup: rbac.authorization.k8s.io
 roleRef:
   kind: Role
   name: {{ ( .Service.ServiceAccountName ) }}
   apiGroup: rbac.authorization.k8s.io
 
+{{ end }}
+`
+
+    namespace = `
+{{ with ( toKubeNamespace . ) }}
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+{{ spaces 2 | toYamlIndent .Metadata }}
 {{ end }}
 `
 
@@ -598,7 +1581,7 @@ roleRef:
 apiVersion: v1
 kind: Secret
 metadata:
-  name: {{ ( .Service.ImagePullSecret.Name ) }}
+{{ spaces 2 | toYamlIndent ( toKubeObjectMeta . "secret" .Service.ImagePullSecret.Name ) }}
 data:
   .dockerconfigjson: {{ ( .Service.ImagePullSecret.Base64EncodedDockerConfigJSON ) }}
 
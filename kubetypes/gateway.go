@@ -0,0 +1,61 @@
+package kubetypes
+
+// KubeHTTPRoute is the metadata and spec of gateway.networking.k8s.io/v1 HTTPRoute
+type KubeHTTPRoute struct {
+	Metadata KubeObjectMeta    `yaml:"metadata"`
+	Spec     KubeHTTPRouteSpec `yaml:"spec"`
+}
+
+type KubeHTTPRouteSpec struct {
+	ParentRefs []ParentReference `yaml:"parentRefs"`
+	Hostnames  []string          `yaml:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `yaml:"rules,omitempty"`
+}
+
+type ParentReference struct {
+	Namespace   string `yaml:"namespace,omitempty"`
+	Name        string `yaml:"name"`
+	SectionName string `yaml:"sectionName,omitempty"`
+}
+
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch  `yaml:"matches,omitempty"`
+	Filters     []HTTPRouteFilter `yaml:"filters,omitempty"`
+	BackendRefs []HTTPBackendRef  `yaml:"backendRefs,omitempty"`
+}
+
+type HTTPRouteMatch struct {
+	Path *HTTPPathMatch `yaml:"path,omitempty"`
+}
+
+type HTTPPathMatchType string
+
+const (
+	HTTPPathMatchExact      HTTPPathMatchType = "Exact"
+	HTTPPathMatchPathPrefix HTTPPathMatchType = "PathPrefix"
+)
+
+type HTTPPathMatch struct {
+	Type  HTTPPathMatchType `yaml:"type"`
+	Value string            `yaml:"value"`
+}
+
+type HTTPRouteFilter struct {
+	Type       string                `yaml:"type"`
+	URLRewrite *HTTPURLRewriteFilter `yaml:"urlRewrite,omitempty"`
+}
+
+type HTTPURLRewriteFilter struct {
+	Path *HTTPPathModifier `yaml:"path,omitempty"`
+}
+
+type HTTPPathModifier struct {
+	Type               string `yaml:"type"`
+	ReplaceFullPath    string `yaml:"replaceFullPath,omitempty"`
+	ReplacePrefixMatch string `yaml:"replacePrefixMatch,omitempty"`
+}
+
+type HTTPBackendRef struct {
+	Name string `yaml:"name"`
+	Port uint16 `yaml:"port"`
+}
@@ -1,8 +1,23 @@
 package kubetypes
 
+// KubeIngress is the metadata and spec of an Ingress object
+type KubeIngress struct {
+    Metadata KubeObjectMeta  `yaml:"metadata"`
+    Spec     KubeIngressSpec `yaml:"spec"`
+}
+
 type KubeIngressSpec struct {
-    Rules []IngressRule `yaml:"rules"`
-    TLS   []IngressTLS  `yaml:"tls,omitempty"`
+    IngressOpts `yaml:",inline"`
+    // networking.k8s.io/v1
+    DefaultBackend *IngressBackend `yaml:"defaultBackend,omitempty"`
+    // extensions/v1beta1 and networking.k8s.io/v1beta1
+    Backend *IngressBackend `yaml:"backend,omitempty"`
+    Rules   []IngressRule   `yaml:"rules,omitempty"`
+    TLS     []IngressTLS    `yaml:"tls,omitempty"`
+}
+
+type IngressOpts struct {
+    IngressClassName string `yaml:"ingressClassName,omitempty" json:"ingressClassName,omitempty"`
 }
 
 type KubeRoleRule struct {
@@ -21,14 +36,36 @@ type HTTPIngressRuleValue struct {
     Paths []HTTPIngressPath `yaml:"paths"`
 }
 
+type PathType string
+
+const (
+    PathTypeExact                  PathType = "Exact"
+    PathTypePrefix                 PathType = "Prefix"
+    PathTypeImplementationSpecific PathType = "ImplementationSpecific"
+)
+
 type HTTPIngressPath struct {
-    Path    string         `yaml:"path,omitempty"`
-    Backend IngressBackend `yaml:"backend"`
+    Path     string         `yaml:"path,omitempty"`
+    PathType PathType       `yaml:"pathType,omitempty"`
+    Backend  IngressBackend `yaml:"backend"`
 }
 
 type IngressBackend struct {
-    ServiceName string `yaml:"serviceName"`
-    ServicePort uint16 `yaml:"servicePort"`
+    // networking.k8s.io/v1
+    Service *IngressServiceBackend `yaml:"service,omitempty"`
+    // extensions/v1beta1 and networking.k8s.io/v1beta1
+    ServiceName string      `yaml:"serviceName,omitempty"`
+    ServicePort IntOrString `yaml:"servicePort,omitempty"`
+}
+
+type IngressServiceBackend struct {
+    Name string             `yaml:"name"`
+    Port ServiceBackendPort `yaml:"port"`
+}
+
+type ServiceBackendPort struct {
+    Name   string `yaml:"name,omitempty"`
+    Number uint16 `yaml:"number,omitempty"`
 }
 
 type IngressTLS struct {
@@ -0,0 +1,5 @@
+package kubetypes
+
+type KubeNamespace struct {
+	Metadata KubeObjectMeta `yaml:"metadata"`
+}
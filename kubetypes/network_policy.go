@@ -0,0 +1,45 @@
+package kubetypes
+
+import (
+	"github.com/go-courier/helmx/constants"
+)
+
+type PolicyType string
+
+const (
+	PolicyTypeIngress PolicyType = "Ingress"
+	PolicyTypeEgress  PolicyType = "Egress"
+)
+
+type KubeNetworkPolicySpec struct {
+	PodSelector LabelSelector              `yaml:"podSelector"`
+	PolicyTypes []PolicyType               `yaml:"policyTypes"`
+	Ingress     []NetworkPolicyIngressRule `yaml:"ingress,omitempty"`
+	Egress      []NetworkPolicyEgressRule  `yaml:"egress,omitempty"`
+}
+
+type NetworkPolicyIngressRule struct {
+	Ports []NetworkPolicyPort `yaml:"ports,omitempty"`
+	From  []NetworkPolicyPeer `yaml:"from,omitempty"`
+}
+
+type NetworkPolicyEgressRule struct {
+	Ports []NetworkPolicyPort `yaml:"ports,omitempty"`
+	To    []NetworkPolicyPeer `yaml:"to,omitempty"`
+}
+
+type NetworkPolicyPort struct {
+	Protocol constants.Protocol `yaml:"protocol,omitempty"`
+	Port     uint16             `yaml:"port,omitempty"`
+}
+
+type NetworkPolicyPeer struct {
+	PodSelector       *LabelSelector `yaml:"podSelector,omitempty"`
+	NamespaceSelector *LabelSelector `yaml:"namespaceSelector,omitempty"`
+	IPBlock           *IPBlock       `yaml:"ipBlock,omitempty"`
+}
+
+type IPBlock struct {
+	CIDR   string   `yaml:"cidr"`
+	Except []string `yaml:"except,omitempty"`
+}
@@ -60,6 +60,7 @@ type KubeContainer struct {
 	KubeContainerPorts `yaml:",inline"`
 	KubeVolumeMounts   `yaml:",inline"`
 	KubeEnv            `yaml:",inline"`
+	KubeEnvFrom        `yaml:",inline"`
 }
 
 type KubeImage struct {
@@ -77,11 +78,27 @@ type KubeEnvVar struct {
 	ValueFrom map[string]map[string]interface{} `yaml:"valueFrom,omitempty"`
 }
 
+type KubeEnvFrom struct {
+	EnvFrom []KubeEnvFromSource `yaml:"envFrom,omitempty"`
+}
+
+type KubeEnvFromSource struct {
+	Prefix       string                   `yaml:"prefix,omitempty"`
+	ConfigMapRef *KubeEnvFromSourceObject `yaml:"configMapRef,omitempty"`
+	SecretRef    *KubeEnvFromSourceObject `yaml:"secretRef,omitempty"`
+}
+
+type KubeEnvFromSourceObject struct {
+	Name     string `yaml:"name"`
+	Optional *bool  `yaml:"optional,omitempty"`
+}
+
 type KubeContainerPorts struct {
 	Ports []KubeContainerPort `yaml:"ports,omitempty"`
 }
 
 type KubeContainerPort struct {
+	Name          string             `yaml:"name,omitempty"`
 	ContainerPort uint16             `yaml:"containerPort"`
 	Protocol      constants.Protocol `yaml:"protocol,omitempty"`
 }
@@ -126,7 +143,7 @@ type ExecAction struct {
 }
 
 type HTTPGetAction struct {
-	Port        uint16       `yaml:"port"`
+	Port        IntOrString  `yaml:"port"`
 	Path        string       `yaml:"path,omitempty"`
 	Host        string       `yaml:"host,omitempty"`
 	Scheme      string       `yaml:"scheme,omitempty"`
@@ -139,8 +156,8 @@ type HTTPHeader struct {
 }
 
 type TCPSocketAction struct {
-	Port uint16 `yaml:"port"`
-	Host string `yaml:"host,omitempty"`
+	Port IntOrString `yaml:"port"`
+	Host string      `yaml:"host,omitempty"`
 }
 
 type KubeHosts struct {
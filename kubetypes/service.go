@@ -7,23 +7,94 @@ import (
 type ServiceType string
 
 const (
-	ServiceTypeClusterIP ServiceType = "ClusterIP"
-	ServiceTypeNodePort  ServiceType = "NodePort"
+	ServiceTypeClusterIP    ServiceType = "ClusterIP"
+	ServiceTypeNodePort     ServiceType = "NodePort"
+	ServiceTypeLoadBalancer ServiceType = "LoadBalancer"
+	ServiceTypeExternalName ServiceType = "ExternalName"
 )
 
+type ServiceTrafficPolicy string
+
+const (
+	ServiceTrafficPolicyCluster ServiceTrafficPolicy = "Cluster"
+	ServiceTrafficPolicyLocal   ServiceTrafficPolicy = "Local"
+)
+
+type ServiceAffinity string
+
+const (
+	ServiceAffinityNone     ServiceAffinity = "None"
+	ServiceAffinityClientIP ServiceAffinity = "ClientIP"
+)
+
+type IPFamilyPolicy string
+
+const (
+	IPFamilyPolicySingleStack      IPFamilyPolicy = "SingleStack"
+	IPFamilyPolicyPreferDualStack  IPFamilyPolicy = "PreferDualStack"
+	IPFamilyPolicyRequireDualStack IPFamilyPolicy = "RequireDualStack"
+)
+
+type ServiceOpts struct {
+	// only for LoadBalancer service
+	LoadBalancerSourceRanges []string `yaml:"loadBalancerSourceRanges,omitempty" json:"loadBalancerSourceRanges,omitempty"`
+	// only for NodePort or LoadBalancer service
+	ExternalTrafficPolicy ServiceTrafficPolicy `yaml:"externalTrafficPolicy,omitempty" json:"externalTrafficPolicy,omitempty"`
+	InternalTrafficPolicy ServiceTrafficPolicy `yaml:"internalTrafficPolicy,omitempty" json:"internalTrafficPolicy,omitempty"`
+	SessionAffinity       ServiceAffinity      `yaml:"sessionAffinity,omitempty" json:"sessionAffinity,omitempty"`
+	IPFamilyPolicy        IPFamilyPolicy       `yaml:"ipFamilyPolicy,omitempty" json:"ipFamilyPolicy,omitempty"`
+}
+
 type KubeServiceSpec struct {
-	ClusterIP *string           `yaml:"clusterIP,omitempty"`
-	Type      ServiceType       `yaml:"type,omitempty"`
-	Ports     []KubeServicePort `yaml:"ports,omitempty"`
+	ClusterIP   *string           `yaml:"clusterIP,omitempty"`
+	Type        ServiceType       `yaml:"type,omitempty"`
+	Ports       []KubeServicePort `yaml:"ports,omitempty"`
+	ServiceOpts `yaml:",inline"`
+	// for ExternalName service
+	ExternalName string `yaml:"externalName,omitempty"`
 }
 
 type KubeServicePort struct {
-	Name       string             `yaml:"name,omitempty"`
-	NodePort   uint16             `yaml:"nodePort,omitempty"`
-	Port       uint16             `yaml:"port"`
-	TargetPort uint16             `yaml:"targetPort"`
-	Protocol   constants.Protocol `yaml:"protocol"`
+	Name        string             `yaml:"name,omitempty"`
+	NodePort    uint16             `yaml:"nodePort,omitempty"`
+	Port        uint16             `yaml:"port"`
+	TargetPort  IntOrString        `yaml:"targetPort,omitempty"`
+	AppProtocol string             `yaml:"appProtocol,omitempty"`
+	Protocol    constants.Protocol `yaml:"protocol"`
 }
 
 type KubeServiceHeadless struct {
 }
+
+// KubeExternalService is service of external upstream,
+// with EndpointSlice when upstream is an ip
+type KubeExternalService struct {
+	Metadata      KubeObjectMeta     `yaml:"metadata"`
+	Spec          KubeServiceSpec    `yaml:"spec"`
+	EndpointSlice *KubeEndpointSlice `yaml:"endpointSlice,omitempty"`
+}
+
+type AddressType string
+
+const (
+	AddressTypeIPv4 AddressType = "IPv4"
+	AddressTypeIPv6 AddressType = "IPv6"
+)
+
+// KubeEndpointSlice is the metadata and fields of discovery.k8s.io/v1 EndpointSlice
+type KubeEndpointSlice struct {
+	Metadata    KubeObjectMeta `yaml:"metadata"`
+	AddressType AddressType    `yaml:"addressType"`
+	Endpoints   []Endpoint     `yaml:"endpoints"`
+	Ports       []EndpointPort `yaml:"ports,omitempty"`
+}
+
+type Endpoint struct {
+	Addresses []string `yaml:"addresses"`
+}
+
+type EndpointPort struct {
+	Name     string             `yaml:"name,omitempty"`
+	Port     uint16             `yaml:"port"`
+	Protocol constants.Protocol `yaml:"protocol,omitempty"`
+}
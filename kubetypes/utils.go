@@ -1,5 +1,9 @@
 package kubetypes
 
+import (
+	"strconv"
+)
+
 type KubeLocalObjectReference struct {
 	Name string `yaml:"name" json:"name" toml:"name"`
 }
@@ -11,7 +15,8 @@ type KubeOption struct {
 
 type KubeMetadata struct {
 	Metadata struct {
-		Labels map[string]string `yaml:"labels,omitempty"`
+		Labels      map[string]string `yaml:"labels,omitempty"`
+		Annotations map[string]string `yaml:"annotations,omitempty"`
 	} `yaml:"metadata,omitempty"`
 }
 
@@ -20,3 +25,82 @@ type KubeSelector struct {
 		MatchLabels map[string]string `yaml:"matchLabels,omitempty"`
 	} `yaml:"selector"`
 }
+
+type KubeObjectMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// WithLabel returns a copy with the label set
+func (m KubeObjectMeta) WithLabel(key string, value string) KubeObjectMeta {
+	m.Labels = withKeyValue(m.Labels, key, value)
+	return m
+}
+
+// WithAnnotation returns a copy with the annotation set
+func (m KubeObjectMeta) WithAnnotation(key string, value string) KubeObjectMeta {
+	m.Annotations = withKeyValue(m.Annotations, key, value)
+	return m
+}
+
+func withKeyValue(values map[string]string, key string, value string) map[string]string {
+	m := make(map[string]string, len(values)+1)
+	for k, v := range values {
+		m[k] = v
+	}
+	m[key] = value
+	return m
+}
+
+// IntOrString is a number or a name, like port of container
+type IntOrString struct {
+	IntVal int32
+	StrVal string
+}
+
+func FromInt(i int) IntOrString {
+	return IntOrString{IntVal: int32(i)}
+}
+
+func FromString(s string) IntOrString {
+	return IntOrString{StrVal: s}
+}
+
+func (v IntOrString) IsString() bool {
+	return v.StrVal != ""
+}
+
+func (v IntOrString) IsZero() bool {
+	return v.StrVal == "" && v.IntVal == 0
+}
+
+func (v IntOrString) String() string {
+	if v.IsString() {
+		return v.StrVal
+	}
+	return strconv.FormatInt(int64(v.IntVal), 10)
+}
+
+func (v IntOrString) MarshalYAML() (interface{}, error) {
+	if v.IsString() {
+		return v.StrVal, nil
+	}
+	return v.IntVal, nil
+}
+
+func (v *IntOrString) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var i int32
+	if err := unmarshal(&i); err == nil {
+		*v = IntOrString{IntVal: i}
+		return nil
+	}
+
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	*v = IntOrString{StrVal: s}
+	return nil
+}
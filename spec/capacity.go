@@ -0,0 +1,264 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type ResourceList map[string]Quantity
+
+func ParseResourceList(values map[string]string) (ResourceList, error) {
+	l := ResourceList{}
+	for k, v := range values {
+		q, err := ParseQuantity(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", k, err)
+		}
+		l[k] = *q
+	}
+	return l, nil
+}
+
+func (l ResourceList) Add(src ResourceList) ResourceList {
+	rl := ResourceList{}
+	for k, v := range l {
+		rl[k] = v
+	}
+	for k, v := range src {
+		rl[k] = rl[k].Add(v)
+	}
+	return rl
+}
+
+func (l ResourceList) Mul(n int64) ResourceList {
+	rl := ResourceList{}
+	for k, v := range l {
+		rl[k] = v.Mul(n)
+	}
+	return rl
+}
+
+// Max returns the larger quantity of each resource type
+func (l ResourceList) Max(src ResourceList) ResourceList {
+	rl := ResourceList{}
+	for k, v := range l {
+		rl[k] = v
+	}
+	for k, v := range src {
+		if current, ok := rl[k]; !ok || v.Cmp(current) > 0 {
+			rl[k] = v
+		}
+	}
+	return rl
+}
+
+func (l ResourceList) String() string {
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, k+"="+l[k].String())
+	}
+	return strings.Join(values, ",")
+}
+
+type CapacityItem struct {
+	// Deployment, Job or CronJob
+	Kind string
+	Name string
+	// replicas of service or parallelism of job
+	Instances int32
+	// cronjob with Allow concurrency policy could run more instances when runs overlapped,
+	// which are not counted in Instances
+	MayOverlap bool
+	// requests and limits of each instance
+	Requests ResourceList
+	Limits   ResourceList
+}
+
+type CapacityReport struct {
+	Items    []CapacityItem
+	Requests ResourceList
+	Limits   ResourceList
+}
+
+// CapacityReport sums requests and limits of all pods the spec will create.
+//
+// Resources of each pod follows kubernetes rules:
+// the larger one of sum of containers and max of init containers,
+// and request defaults to limit when only limit set.
+//
+// Not counted, so actual usage could be more than the report:
+//
+//	init containers of waitForUpstreams, which are rendered without resources and take defaults of LimitRange if any
+//	overlapped runs of CronJob with Allow concurrency policy, only marked by MayOverlap
+//	HorizontalPodAutoscaler, which is not part of spec, only replicas of service counted
+//	pod overhead of RuntimeClass
+func (s Spec) CapacityReport() *CapacityReport {
+	r := &CapacityReport{
+		Requests: ResourceList{},
+		Limits:   ResourceList{},
+	}
+
+	if s.Service != nil {
+		item := s.capacityItem("Deployment", s.Project.FullName(), s.Service.Pod)
+
+		item.Instances = 1
+		if s.Service.Replicas != nil {
+			item.Instances = *s.Service.Replicas
+		}
+
+		r.add(*item)
+	}
+
+	names := make([]string, 0, len(s.Jobs))
+	for name := range s.Jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		job := s.Jobs[name]
+
+		kind := "Job"
+		if job.Cron != nil {
+			kind = "CronJob"
+		}
+
+		item := s.capacityItem(kind, JoinName(s.Project.FullName(), name), job.Pod)
+
+		item.Instances = 1
+		if job.Parallelism != nil {
+			item.Instances = *job.Parallelism
+		}
+
+		if job.Cron != nil {
+			switch job.Cron.ConcurrencyPolicy {
+			case "Forbid", "Replace":
+			default:
+				item.MayOverlap = true
+			}
+		}
+
+		r.add(*item)
+	}
+
+	return r
+}
+
+func (s Spec) capacityItem(kind string, name string, pod Pod) *CapacityItem {
+	item := &CapacityItem{
+		Kind:     kind,
+		Name:     name,
+		Requests: ResourceList{},
+		Limits:   ResourceList{},
+	}
+
+	initRequests, initLimits := ResourceList{}, ResourceList{}
+
+	for _, c := range pod.Initials {
+		requests, limits := s.Resources.Merge(c.Resources).requestsAndLimits()
+		initRequests = initRequests.Max(requests)
+		initLimits = initLimits.Max(limits)
+	}
+
+	requests, limits := s.Resources.Merge(pod.Resources).requestsAndLimits()
+
+	item.Requests = requests.Max(initRequests)
+	item.Limits = limits.Max(initLimits)
+
+	return item
+}
+
+func (resources Resources) requestsAndLimits() (ResourceList, ResourceList) {
+	requests, limits := ResourceList{}, ResourceList{}
+
+	for resourceType, r := range resources {
+		if r == nil {
+			continue
+		}
+
+		if !r.Limit.IsZero() {
+			limits[resourceType] = r.Limit
+		}
+
+		if !r.Request.IsZero() {
+			requests[resourceType] = r.Request
+		} else if !r.Limit.IsZero() {
+			requests[resourceType] = r.Limit
+		}
+	}
+
+	return requests, limits
+}
+
+func (r *CapacityReport) add(item CapacityItem) {
+	r.Items = append(r.Items, item)
+	r.Requests = r.Requests.Add(item.Requests.Mul(int64(item.Instances)))
+	r.Limits = r.Limits.Add(item.Limits.Mul(int64(item.Instances)))
+}
+
+// CheckBudget returns error when any resource of the budget exceeded.
+// keys of budget follow ResourceQuota, like requests.cpu, limits.memory, requests.nvidia.com/gpu,
+// and cpu, memory without prefix for requests.
+//
+// only resources counted by CapacityReport are checked, see Spec.CapacityReport for what is not counted.
+func (r CapacityReport) CheckBudget(budget ResourceList) error {
+	keys := make([]string, 0, len(budget))
+	for k := range budget {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	exceeded := make([]string, 0)
+
+	for _, k := range keys {
+		used := r.Requests[k]
+
+		switch {
+		case strings.HasPrefix(k, "requests."):
+			used = r.Requests[strings.TrimPrefix(k, "requests.")]
+		case strings.HasPrefix(k, "limits."):
+			used = r.Limits[strings.TrimPrefix(k, "limits.")]
+		}
+
+		if used.Cmp(budget[k]) > 0 {
+			exceeded = append(exceeded, fmt.Sprintf("%s %s > %s", k, used, budget[k]))
+		}
+	}
+
+	if len(exceeded) > 0 {
+		return fmt.Errorf("budget exceeded: %s", strings.Join(exceeded, ", "))
+	}
+
+	return nil
+}
+
+func (r CapacityReport) String() string {
+	buf := bytes.NewBuffer(nil)
+
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "KIND\tNAME\tINSTANCES\tREQUESTS\tLIMITS")
+
+	for _, item := range r.Items {
+		instances := fmt.Sprintf("%d", item.Instances)
+		if item.MayOverlap {
+			instances += "+"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", item.Kind, item.Name, instances, item.Requests, item.Limits)
+	}
+
+	_, _ = fmt.Fprintf(w, "TOTAL\t\t\t%s\t%s\n", r.Requests, r.Limits)
+
+	_ = w.Flush()
+
+	return buf.String()
+}
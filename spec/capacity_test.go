@@ -0,0 +1,91 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCapacityReport(t *testing.T) {
+	s := Spec{}
+
+	err := yaml.Unmarshal([]byte(`
+project:
+  name: helmx
+  version: 0.0.0
+service:
+  replicas: 3
+  initials:
+    - image: busybox
+      resources:
+        memory: 2Gi
+jobs:
+  migrate:
+    image: busybox
+    parallelism: 2
+    resources:
+      memory: 64Mi/128Mi
+  cleanup:
+    image: busybox
+    cron:
+      schedule: "*/1 * * * *"
+resources:
+  cpu: 500m/1
+  memory: 1Gi/4Gi
+  nvidia.com/gpu: /1
+`), &s)
+	require.NoError(t, err)
+
+	r := s.CapacityReport()
+
+	require.Len(t, r.Items, 3)
+
+	require.Equal(t, "Deployment", r.Items[0].Kind)
+	require.Equal(t, int32(3), r.Items[0].Instances)
+	// init container requests 2Gi more than the container
+	require.Equal(t, "2Gi", r.Items[0].Requests["memory"].String())
+	require.Equal(t, "4Gi", r.Items[0].Limits["memory"].String())
+	// request defaults to limit
+	require.Equal(t, "1", r.Items[0].Requests["nvidia.com/gpu"].String())
+
+	require.Equal(t, "CronJob", r.Items[1].Kind)
+	require.Equal(t, "helmx--cleanup", r.Items[1].Name)
+	require.Equal(t, true, r.Items[1].MayOverlap)
+
+	require.Equal(t, "Job", r.Items[2].Kind)
+	require.Equal(t, int32(2), r.Items[2].Instances)
+
+	// 3 * 500m + 500m + 2 * 500m
+	require.Equal(t, "3", r.Requests["cpu"].String())
+	// 3 * 2Gi + 1Gi + 2 * 64Mi
+	require.Equal(t, "7296Mi", r.Requests["memory"].String())
+	require.Equal(t, "6", r.Requests["nvidia.com/gpu"].String())
+	require.Equal(t, "6", r.Limits["nvidia.com/gpu"].String())
+
+	t.Run("check budget", func(t *testing.T) {
+		budget, err := ParseResourceList(map[string]string{
+			"cpu":           "4",
+			"limits.memory": "17Gi",
+		})
+		require.NoError(t, err)
+		require.NoError(t, r.CheckBudget(budget))
+
+		budget, err = ParseResourceList(map[string]string{
+			"requests.cpu":            "2",
+			"limits.memory":           "16Gi",
+			"requests.nvidia.com/gpu": "4",
+		})
+		require.NoError(t, err)
+		require.EqualError(t, r.CheckBudget(budget), "budget exceeded: limits.memory 16640Mi > 16Gi, requests.cpu 3 > 2, requests.nvidia.com/gpu 6 > 4")
+	})
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, `KIND        NAME            INSTANCES  REQUESTS                               LIMITS
+Deployment  helmx           3          cpu=500m,memory=2Gi,nvidia.com/gpu=1   cpu=1,memory=4Gi,nvidia.com/gpu=1
+CronJob     helmx--cleanup  1+         cpu=500m,memory=1Gi,nvidia.com/gpu=1   cpu=1,memory=4Gi,nvidia.com/gpu=1
+Job         helmx--migrate  2          cpu=500m,memory=64Mi,nvidia.com/gpu=1  cpu=1,memory=128Mi,nvidia.com/gpu=1
+TOTAL                                  cpu=3,memory=7296Mi,nvidia.com/gpu=6   cpu=6,memory=16640Mi,nvidia.com/gpu=6
+`, r.String())
+	})
+}
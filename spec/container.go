@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/url"
 	"os"
 	"strconv"
@@ -25,7 +26,9 @@ type Container struct {
 	Args       []string      `json:"args,omitempty" yaml:"args,omitempty"`
 	Mounts     []VolumeMount `json:"mounts,omitempty" yaml:"mounts,omitempty"`
 	Envs       Envs          `json:"envs,omitempty" yaml:"envs,omitempty"`
+	EnvFrom    []EnvFrom     `json:"envFrom,omitempty" yaml:"envFrom,omitempty"`
 	TTY        bool          `json:"tty,omitempty" yaml:"tty,omitempty"`
+	Resources  Resources     `json:"resources,omitempty" yaml:"resources,omitempty"`
 
 	ReadinessProbe                          *Probe                     `json:"readinessProbe,omitempty" yaml:"readinessProbe,omitempty"`
 	LivenessProbe                           *Probe                     `json:"livenessProbe,omitempty" yaml:"livenessProbe,omitempty"`
@@ -46,8 +49,10 @@ type Probe struct {
 }
 
 type Image struct {
-	// default as project.group/project.name:version
+	// default as project.group/project.name:version, see ParseImageReference
 	Tag string `json:"image,omitempty" yaml:"image,omitempty"`
+	// pin image by digest, like sha256:<hex>
+	Digest string `json:"imageDigest,omitempty" yaml:"imageDigest,omitempty"`
 	// <schema_name>://<host>/[prefix-]
 	ImagePullSecret *ImagePullSecret     `json:"imagePullSecret,omitempty" yaml:"imagePullSecret,omitempty"`
 	ImagePullPolicy constants.PullPolicy `json:"imagePullPolicy,omitempty" yaml:"imagePullPolicy,omitempty"`
@@ -61,6 +66,33 @@ func (i Image) ImageTag(defaultTag string) string {
 	return i.ResolveImagePullSecret().PrefixTag(i.Tag)
 }
 
+// ImageReference returns reference of image with ~ resolved, pinned by Digest when set
+func (i Image) ImageReference(defaultTag string) (*ImageReference, error) {
+	ref, err := ParseImageReference(i.ImageTag(defaultTag))
+	if err != nil {
+		return nil, err
+	}
+
+	if i.Digest != "" {
+		pinned, err := ref.WithDigest(i.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image digest: %s", err)
+		}
+		ref = &pinned
+	}
+
+	return ref, nil
+}
+
+// ResolveImagePullPolicy returns ImagePullPolicy, Always when unset and image is latest or untagged,
+// otherwise empty as IfNotPresent is the default of kubelet
+func (i Image) ResolveImagePullPolicy(ref ImageReference) constants.PullPolicy {
+	if i.ImagePullPolicy == "" && ref.IsLatest() {
+		return constants.PullAlways
+	}
+	return i.ImagePullPolicy
+}
+
 func (i Image) ResolveImagePullSecret() *ImagePullSecret {
 	imagePullSecret := i.ImagePullSecret
 
@@ -172,6 +204,7 @@ type AuthConfig struct {
 }
 
 // http://:80
+// http://:http-80/healthz with port name
 // tcp://:80
 // exec
 func ParseAction(s string) (*Action, error) {
@@ -182,22 +215,26 @@ func ParseAction(s string) (*Action, error) {
 	a := &Action{}
 
 	if strings.HasPrefix(s, "http") || strings.HasPrefix(s, "tcp") {
-		u, err := url.Parse(s)
+		u, portName, err := parseURLWithPortName(s)
 		if err != nil {
 			return nil, err
 		}
 
-		port, _ := strconv.ParseUint(u.Port(), 10, 64)
+		port := kubetypes.FromString(portName)
+		if portName == "" {
+			p, _ := strconv.ParseUint(u.Port(), 10, 16)
+			port = kubetypes.FromInt(int(p))
+		}
 
 		if u.Scheme == "tcp" {
 			a.TCPSocket = &kubetypes.TCPSocketAction{}
 			a.TCPSocket.Host = u.Hostname()
-			a.TCPSocket.Port = uint16(port)
+			a.TCPSocket.Port = port
 			return a, nil
 		}
 
 		a.HTTPGet = &kubetypes.HTTPGetAction{}
-		a.HTTPGet.Port = uint16(port)
+		a.HTTPGet.Port = port
 		a.HTTPGet.Host = u.Hostname()
 		a.HTTPGet.Path = u.Path
 		a.HTTPGet.Scheme = strings.ToUpper(u.Scheme)
@@ -226,7 +263,7 @@ func (a Action) String() string {
 		u := &url.URL{}
 		u.Scheme = strings.ToLower(a.HTTPGet.Scheme)
 		u.Path = a.HTTPGet.Path
-		u.Host = a.HTTPGet.Host + ":" + strconv.FormatUint(uint64(a.HTTPGet.Port), 10)
+		u.Host = a.HTTPGet.Host + ":" + a.HTTPGet.Port.String()
 
 		if u.Scheme != "" {
 			u.Scheme = "http"
@@ -237,7 +274,7 @@ func (a Action) String() string {
 	if a.TCPSocket != nil {
 		u := &url.URL{}
 		u.Scheme = "tcp"
-		u.Host = a.TCPSocket.Host + ":" + strconv.FormatUint(uint64(a.TCPSocket.Port), 10)
+		u.Host = a.TCPSocket.Host + ":" + a.TCPSocket.Port.String()
 
 		return u.String()
 	}
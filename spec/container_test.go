@@ -3,6 +3,7 @@ package spec
 import (
 	"testing"
 
+	"github.com/go-courier/helmx/kubetypes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -10,7 +11,7 @@ func TestAction(t *testing.T) {
 	t.Run("parse & string http", func(t *testing.T) {
 		action, _ := ParseAction("http://:80/healthy")
 
-		require.Equal(t, uint16(80), action.HTTPGet.Port)
+		require.Equal(t, kubetypes.FromInt(80), action.HTTPGet.Port)
 		require.Equal(t, "HTTP", action.HTTPGet.Scheme)
 		require.Equal(t, "", action.HTTPGet.Host)
 		require.Equal(t, "/healthy", action.HTTPGet.Path)
@@ -21,11 +22,24 @@ func TestAction(t *testing.T) {
 	t.Run("parse & string tcp", func(t *testing.T) {
 		action, _ := ParseAction("tcp://:80")
 
-		require.Equal(t, uint16(80), action.TCPSocket.Port)
+		require.Equal(t, kubetypes.FromInt(80), action.TCPSocket.Port)
 		require.Equal(t, "", action.TCPSocket.Host)
 
 		require.Equal(t, "tcp://:80", action.String())
 	})
+
+	t.Run("parse & string port name", func(t *testing.T) {
+		action, err := ParseAction("http://:http-80/healthy")
+		require.NoError(t, err)
+
+		require.Equal(t, kubetypes.FromString("http-80"), action.HTTPGet.Port)
+		require.Equal(t, "/healthy", action.HTTPGet.Path)
+
+		require.Equal(t, "http://:http-80/healthy", action.String())
+
+		_, err = ParseAction("tcp://:Invalid_Name")
+		require.Error(t, err)
+	})
 }
 
 func TestToleration(t *testing.T) {
@@ -1,8 +1,12 @@
 package spec
 
 import (
+    "errors"
     "fmt"
+    "net/url"
     "regexp"
+    "sort"
+    "strconv"
     "strings"
 )
 
@@ -22,11 +26,17 @@ func (envs Envs) Merge(srcEnvs Envs) Envs {
 type EnvsWithValueFrom map[string]*EnvValue
 
 func ParseEnvsWithValueFrom(envMap Envs) (EnvsWithValueFrom, error) {
+    keys := make([]string, 0, len(envMap))
+    for k := range envMap {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+
     envs := make(EnvsWithValueFrom)
-    for k, v := range envMap {
-        envValue, err := ParseEnvValue(v)
+    for _, k := range keys {
+        envValue, err := ParseEnvValue(envMap[k])
         if err != nil {
-            return nil, err
+            return nil, fmt.Errorf("env %s: %w", k, err)
         }
         envs[k] = envValue
     }
@@ -52,10 +62,32 @@ func (c *EnvValueFromSecret) String() string {
     return fmt.Sprintf("%s|%s|%v", c.SecretName, c.Key, c.Optional)
 }
 
+// downward api, like status.podIP, spec.nodeName, metadata.namespace
+type EnvValueFromField struct {
+    FieldPath string `json:"fieldPath" yaml:"fieldPath"`
+}
+
+func (c *EnvValueFromField) String() string {
+    return c.FieldPath
+}
+
+type EnvValueFromResource struct {
+    // limits.cpu, limits.memory, requests.cpu, requests.memory
+    Resource      string `json:"resource" yaml:"resource"`
+    ContainerName string `json:"containerName,omitempty" yaml:"containerName,omitempty"`
+    Divisor       string `json:"divisor,omitempty" yaml:"divisor,omitempty"`
+}
+
+func (c *EnvValueFromResource) String() string {
+    return fmt.Sprintf("%s|%s|%s", c.Resource, c.ContainerName, c.Divisor)
+}
+
 type EnvValue struct {
     Value              string                 `json:"value" yaml:"value"`
     ValueFromConfigMap *EnvValueFromConfigMap `json:"valueFromConfigMap,omitempty" yaml:"valueFromConfigMap,omitempty"`
     ValueFromSecret    *EnvValueFromSecret    `json:"envValueFromSecret,omitempty" yaml:"envValueFromSecret,omitempty"`
+    ValueFromField     *EnvValueFromField     `json:"valueFromField,omitempty" yaml:"valueFromField,omitempty"`
+    ValueFromResource  *EnvValueFromResource  `json:"valueFromResource,omitempty" yaml:"valueFromResource,omitempty"`
 }
 
 func (c *EnvValue) String() string {
@@ -67,6 +99,14 @@ func (c *EnvValue) String() string {
     if c.ValueFromSecret != nil {
         return c.ValueFromSecret.String()
     }
+
+    if c.ValueFromField != nil {
+        return c.ValueFromField.String()
+    }
+
+    if c.ValueFromResource != nil {
+        return c.ValueFromResource.String()
+    }
     return c.Value
 }
 
@@ -93,6 +133,10 @@ func IsValueFrom(value string) (bool, string) {
     return true, result["valueFrom"]
 }
 
+// ####configMapName.key####
+// ####secretName.key.optional####
+// ####field:status.podIP####
+// ####resource:limits.memory?divisor=1Mi&container=app####
 func ParseEnvValue(value string) (*EnvValue, error) {
     envValue := &EnvValue{}
     isValueFrom, v := IsValueFrom(value)
@@ -100,7 +144,32 @@ func ParseEnvValue(value string) (*EnvValue, error) {
         envValue.Value = value
         return envValue, nil
     }
+
+    if strings.HasPrefix(v, envValueFromFieldPrefix) {
+        fieldPath := strings.TrimPrefix(v, envValueFromFieldPrefix)
+        if !isValidFieldPath(fieldPath) {
+            return nil, fmt.Errorf("%w: unsupported field path %q", ErrInvalidEnvReference, fieldPath)
+        }
+        envValue.ValueFromField = &EnvValueFromField{FieldPath: fieldPath}
+        return envValue, nil
+    }
+
+    if strings.HasPrefix(v, envValueFromResourcePrefix) {
+        valueFromResource, err := parseEnvValueFromResource(strings.TrimPrefix(v, envValueFromResourcePrefix))
+        if err != nil {
+            return nil, err
+        }
+        envValue.ValueFromResource = valueFromResource
+        return envValue, nil
+    }
+
     valueStr := strings.Split(v, ".")
+    for _, part := range valueStr {
+        if part == "" {
+            return nil, fmt.Errorf("%w: empty part in %q", ErrInvalidEnvReference, value)
+        }
+    }
+
     switch len(valueStr) {
     case 2:
         envValue.ValueFromConfigMap = &EnvValueFromConfigMap{}
@@ -115,8 +184,184 @@ func ParseEnvValue(value string) (*EnvValue, error) {
         } else if strings.ToLower(valueStr[2]) == "false" {
             envValue.ValueFromSecret.Optional = false
         } else {
-            return nil, fmt.Errorf("secret optional str error, should be  true or false")
+            return nil, fmt.Errorf("%w: secret optional should be true or false, but got %q", ErrInvalidEnvReference, valueStr[2])
         }
+    default:
+        return nil, fmt.Errorf("%w: %q should be ####configMapName.key####, ####secretName.key.optional####, ####field:fieldPath#### or ####resource:resource####", ErrInvalidEnvReference, value)
     }
     return envValue, nil
 }
+
+var (
+    ErrInvalidEnvReference = errors.New("invalid env reference")
+)
+
+const (
+    envValueFromFieldPrefix    = "field:"
+    envValueFromResourcePrefix = "resource:"
+)
+
+var fieldPaths = map[string]bool{
+    "metadata.name":           true,
+    "metadata.namespace":      true,
+    "metadata.uid":            true,
+    "spec.nodeName":           true,
+    "spec.serviceAccountName": true,
+    "status.hostIP":           true,
+    "status.hostIPs":          true,
+    "status.podIP":            true,
+    "status.podIPs":           true,
+}
+
+var reLabelOrAnnotationFieldPath = regexp.MustCompile(`^metadata\.(labels|annotations)\['[^']+'\]$`)
+
+func isValidFieldPath(fieldPath string) bool {
+    return fieldPaths[fieldPath] || reLabelOrAnnotationFieldPath.MatchString(fieldPath)
+}
+
+var reResource = regexp.MustCompile(`^(limits|requests)\.[a-z0-9]([a-z0-9.\-/]*[a-z0-9])?$`)
+
+func parseEnvValueFromResource(s string) (*EnvValueFromResource, error) {
+    r := &EnvValueFromResource{}
+
+    query := ""
+    if i := strings.Index(s, "?"); i >= 0 {
+        query = s[i+1:]
+        s = s[0:i]
+    }
+
+    if !reResource.MatchString(s) {
+        return nil, fmt.Errorf("%w: unsupported resource %q, should be limits.<name> or requests.<name>", ErrInvalidEnvReference, s)
+    }
+
+    r.Resource = s
+
+    if query != "" {
+        values, err := url.ParseQuery(query)
+        if err != nil {
+            return nil, fmt.Errorf("%w: invalid resource options %s: %s", ErrInvalidEnvReference, query, err)
+        }
+
+        for k := range values {
+            switch k {
+            case "divisor":
+                r.Divisor = values.Get(k)
+            case "container":
+                r.ContainerName = values.Get(k)
+            default:
+                return nil, fmt.Errorf("%w: unsupported resource option %s", ErrInvalidEnvReference, k)
+            }
+        }
+    }
+
+    return r, nil
+}
+
+// configmap:app-config
+// secret:db-creds?optional
+// secret:db-creds?optional&prefix=DB_
+func ParseEnvFrom(s string) (*EnvFrom, error) {
+    if s == "" {
+        return nil, fmt.Errorf("missing env from value")
+    }
+
+    e := &EnvFrom{}
+
+    source := s
+    query := ""
+
+    if i := strings.Index(s, "?"); i >= 0 {
+        source = s[0:i]
+        query = s[i+1:]
+    }
+
+    parts := strings.Split(source, ":")
+    if len(parts) != 2 || parts[1] == "" {
+        return nil, fmt.Errorf("invalid env from %s, should be configmap:<name> or secret:<name>", s)
+    }
+
+    switch strings.ToLower(parts[0]) {
+    case "configmap":
+        e.ConfigMapName = parts[1]
+    case "secret":
+        e.SecretName = parts[1]
+    default:
+        return nil, fmt.Errorf("invalid env from source %s, should be configmap or secret", parts[0])
+    }
+
+    if query != "" {
+        values, err := url.ParseQuery(query)
+        if err != nil {
+            return nil, fmt.Errorf("invalid env from options %s: %s", query, err)
+        }
+
+        for k := range values {
+            switch k {
+            case "optional":
+                // optional without value as true
+                if v := values.Get(k); v != "" {
+                    optional, err := strconv.ParseBool(v)
+                    if err != nil {
+                        return nil, fmt.Errorf("env from option optional should be true or false, but got %q", v)
+                    }
+                    e.Optional = optional
+                } else {
+                    e.Optional = true
+                }
+            case "prefix":
+                e.Prefix = values.Get(k)
+            default:
+                return nil, fmt.Errorf("unsupported env from option %s", k)
+            }
+        }
+    }
+
+    return e, nil
+}
+
+// openapi:strfmt env-from
+type EnvFrom struct {
+    ConfigMapName string
+    SecretName    string
+    Prefix        string
+    Optional      bool
+}
+
+func (e EnvFrom) String() string {
+    v := ""
+
+    if e.SecretName != "" {
+        v = "secret:" + e.SecretName
+    } else {
+        v = "configmap:" + e.ConfigMapName
+    }
+
+    options := make([]string, 0)
+
+    if e.Optional {
+        options = append(options, "optional")
+    }
+
+    if e.Prefix != "" {
+        options = append(options, "prefix="+url.QueryEscape(e.Prefix))
+    }
+
+    if len(options) > 0 {
+        v += "?" + strings.Join(options, "&")
+    }
+
+    return v
+}
+
+func (e EnvFrom) MarshalText() ([]byte, error) {
+    return []byte(e.String()), nil
+}
+
+func (e *EnvFrom) UnmarshalText(data []byte) error {
+    envFrom, err := ParseEnvFrom(string(data))
+    if err != nil {
+        return err
+    }
+    *e = *envFrom
+    return nil
+}
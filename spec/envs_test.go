@@ -1,8 +1,11 @@
 package spec
 
 import (
-    "github.com/stretchr/testify/require"
+    "errors"
     "testing"
+
+    "github.com/stretchr/testify/require"
+    "gopkg.in/yaml.v2"
 )
 
 func TestIsValueFrom(t *testing.T) {
@@ -68,25 +71,71 @@ func TestParseEnvValue(t *testing.T) {
     })
 
     t.Run("configmap", func(t *testing.T) {
-        envValue, _ := ParseEnvValue("configMapName.configMapKey")
+        envValue, _ := ParseEnvValue("####configMapName.configMapKey####")
         require.Equal(t, "configMapName", envValue.ValueFromConfigMap.ConfigMapName)
         require.Equal(t, "configMapKey", envValue.ValueFromConfigMap.Key)
     })
 
     t.Run("secret_true", func(t *testing.T) {
-        envValue, _ := ParseEnvValue("secretName.secretKey.true")
+        envValue, _ := ParseEnvValue("####secretName.secretKey.true####")
         require.Equal(t, "secretName", envValue.ValueFromSecret.SecretName)
         require.Equal(t, "secretKey", envValue.ValueFromSecret.Key)
         require.Equal(t, true, envValue.ValueFromSecret.Optional)
     })
 
     t.Run("secret_false", func(t *testing.T) {
-        envValue, _ := ParseEnvValue("secretName.secretKey.false")
+        envValue, _ := ParseEnvValue("####secretName.secretKey.false####")
         require.Equal(t, "secretName", envValue.ValueFromSecret.SecretName)
         require.Equal(t, "secretKey", envValue.ValueFromSecret.Key)
         require.Equal(t, false, envValue.ValueFromSecret.Optional)
     })
 
+    t.Run("field", func(t *testing.T) {
+        envValue, err := ParseEnvValue("####field:status.podIP####")
+        require.NoError(t, err)
+        require.Equal(t, "status.podIP", envValue.ValueFromField.FieldPath)
+    })
+
+    t.Run("resource", func(t *testing.T) {
+        envValue, err := ParseEnvValue("####resource:limits.memory####")
+        require.NoError(t, err)
+        require.Equal(t, "limits.memory", envValue.ValueFromResource.Resource)
+        require.Equal(t, "", envValue.ValueFromResource.Divisor)
+    })
+
+    t.Run("resource with options", func(t *testing.T) {
+        envValue, err := ParseEnvValue("####resource:limits.memory?divisor=1Mi&container=app####")
+        require.NoError(t, err)
+        require.Equal(t, "limits.memory", envValue.ValueFromResource.Resource)
+        require.Equal(t, "1Mi", envValue.ValueFromResource.Divisor)
+        require.Equal(t, "app", envValue.ValueFromResource.ContainerName)
+    })
+
+    t.Run("invalid field or resource", func(t *testing.T) {
+        _, err := ParseEnvValue("####field:####")
+        require.Error(t, err)
+
+        _, err = ParseEnvValue("####resource:limits.cpu?unknown=1####")
+        require.Error(t, err)
+
+        _, err = ParseEnvValue("####field:status.unknown####")
+        require.Error(t, err)
+
+        _, err = ParseEnvValue("####resource:memory####")
+        require.Error(t, err)
+    })
+
+    t.Run("invalid reference", func(t *testing.T) {
+        for _, v := range []string{
+            "####a####",
+            "####a.b.c.d####",
+            "####a..b####",
+            "####secretName.secretKey.yes####",
+        } {
+            _, err := ParseEnvValue(v)
+            require.True(t, errors.Is(err, ErrInvalidEnvReference), v)
+        }
+    })
 }
 
 func TestParseEnvs(t *testing.T) {
@@ -100,7 +149,7 @@ func TestParseEnvs(t *testing.T) {
     })
 
     t.Run("configmap", func(t *testing.T) {
-        envs, _ := ParseEnvsWithValueFrom(map[string]string{"key": "configmapName.configMapKey"})
+        envs, _ := ParseEnvsWithValueFrom(map[string]string{"key": "####configmapName.configMapKey####"})
         expectEnvs := make(EnvsWithValueFrom)
         expectEnvs["key"] = &EnvValue{
             ValueFromConfigMap: &EnvValueFromConfigMap{
@@ -112,7 +161,7 @@ func TestParseEnvs(t *testing.T) {
     })
 
     t.Run("secret", func(t *testing.T) {
-        envs, _ := ParseEnvsWithValueFrom(map[string]string{"key": "secretName.secretKey.true"})
+        envs, _ := ParseEnvsWithValueFrom(map[string]string{"key": "####secretName.secretKey.true####"})
         expectEnvs := make(EnvsWithValueFrom)
         expectEnvs["key"] = &EnvValue{
             ValueFromSecret: &EnvValueFromSecret{
@@ -126,8 +175,8 @@ func TestParseEnvs(t *testing.T) {
 
     t.Run("mixed", func(t *testing.T) {
         envs, _ := ParseEnvsWithValueFrom(map[string]string{
-            "key1": "secretName.secretKey.true",
-            "key2": "configmapName.configMapKey",
+            "key1": "####secretName.secretKey.true####",
+            "key2": "####configmapName.configMapKey####",
             "key3": "value",
         })
         expectEnvs := make(EnvsWithValueFrom)
@@ -150,4 +199,79 @@ func TestParseEnvs(t *testing.T) {
         require.Equal(t, expectEnvs, envs)
     })
 
+    t.Run("invalid with env key", func(t *testing.T) {
+        _, err := ParseEnvsWithValueFrom(map[string]string{
+            "key1": "value",
+            "key2": "####configmapName####",
+        })
+        require.EqualError(t, err, `env key2: invalid env reference: "####configmapName####" should be ####configMapName.key####, ####secretName.key.optional####, ####field:fieldPath#### or ####resource:resource####`)
+    })
+}
+
+func TestEnvFrom(t *testing.T) {
+    t.Run("parse & string configmap", func(t *testing.T) {
+        e, err := ParseEnvFrom("configmap:app-config")
+        require.NoError(t, err)
+        require.Equal(t, "app-config", e.ConfigMapName)
+        require.Equal(t, "", e.SecretName)
+        require.Equal(t, false, e.Optional)
+
+        require.Equal(t, "configmap:app-config", e.String())
+    })
+
+    t.Run("parse & string secret with options", func(t *testing.T) {
+        e, err := ParseEnvFrom("secret:db-creds?optional&prefix=DB_")
+        require.NoError(t, err)
+        require.Equal(t, "db-creds", e.SecretName)
+        require.Equal(t, true, e.Optional)
+        require.Equal(t, "DB_", e.Prefix)
+
+        require.Equal(t, "secret:db-creds?optional&prefix=DB_", e.String())
+    })
+
+    t.Run("parse optional with value", func(t *testing.T) {
+        e, err := ParseEnvFrom("secret:db-creds?optional=false")
+        require.NoError(t, err)
+        require.Equal(t, false, e.Optional)
+        require.Equal(t, "secret:db-creds", e.String())
+
+        e, err = ParseEnvFrom("secret:db-creds?optional=true")
+        require.NoError(t, err)
+        require.Equal(t, true, e.Optional)
+    })
+
+    t.Run("invalid", func(t *testing.T) {
+        _, err := ParseEnvFrom("app-config")
+        require.Error(t, err)
+
+        _, err = ParseEnvFrom("volume:app-config")
+        require.Error(t, err)
+
+        _, err = ParseEnvFrom("secret:db-creds?unknown")
+        require.Error(t, err)
+
+        _, err = ParseEnvFrom("secret:db-creds?optional=maybe")
+        require.Error(t, err)
+    })
+
+    t.Run("yaml marshal & unmarshal", func(t *testing.T) {
+        data, err := yaml.Marshal(struct {
+            EnvFrom []EnvFrom `yaml:"envFrom"`
+        }{
+            EnvFrom: []EnvFrom{
+                {ConfigMapName: "app-config"},
+                {SecretName: "db-creds", Optional: true},
+            },
+        })
+        require.NoError(t, err)
+        require.Equal(t, "envFrom:\n- configmap:app-config\n- secret:db-creds?optional\n", string(data))
+
+        v := struct {
+            EnvFrom []EnvFrom `yaml:"envFrom"`
+        }{}
+
+        err = yaml.Unmarshal(data, &v)
+        require.NoError(t, err)
+        require.Equal(t, "secret:db-creds?optional", v.EnvFrom[1].String())
+    })
 }
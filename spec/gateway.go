@@ -0,0 +1,99 @@
+package spec
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseGatewayRef parses reference of Gateway
+//
+//	gateway
+//	gateway-system/public
+//	gateway-system/public?http=web&https=websecure
+//
+// http and https are section names of listeners for routes without and with tls
+func ParseGatewayRef(s string) (*GatewayRef, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing gateway")
+	}
+
+	g := &GatewayRef{}
+
+	if i := strings.Index(s, "?"); i >= 0 {
+		query, err := url.ParseQuery(s[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid gateway %q: %s", s, err)
+		}
+		g.HTTPSectionName = query.Get("http")
+		g.HTTPSSectionName = query.Get("https")
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, "/")
+
+	switch len(parts) {
+	case 1:
+		g.Name = parts[0]
+	case 2:
+		g.Namespace = parts[0]
+		g.Name = parts[1]
+	default:
+		return nil, fmt.Errorf("invalid gateway %q, should be [<namespace>/]<name>", s)
+	}
+
+	if g.Name == "" {
+		return nil, fmt.Errorf("invalid gateway %q, missing name", s)
+	}
+
+	return g, nil
+}
+
+// openapi:strfmt gateway-ref
+type GatewayRef struct {
+	Namespace        string
+	Name             string
+	HTTPSectionName  string
+	HTTPSSectionName string
+}
+
+// SectionName returns section name of listener for route with or without tls
+func (g GatewayRef) SectionName(tls bool) string {
+	if tls {
+		return g.HTTPSSectionName
+	}
+	return g.HTTPSectionName
+}
+
+func (g GatewayRef) String() string {
+	s := g.Name
+	if g.Namespace != "" {
+		s = g.Namespace + "/" + s
+	}
+
+	query := url.Values{}
+	if g.HTTPSectionName != "" {
+		query.Set("http", g.HTTPSectionName)
+	}
+	if g.HTTPSSectionName != "" {
+		query.Set("https", g.HTTPSSectionName)
+	}
+	if len(query) > 0 {
+		s += "?" + query.Encode()
+	}
+
+	return s
+}
+
+func (g GatewayRef) MarshalText() ([]byte, error) {
+	return []byte(g.String()), nil
+}
+
+func (g *GatewayRef) UnmarshalText(data []byte) error {
+	gateway, err := ParseGatewayRef(string(data))
+	if err != nil {
+		return err
+	}
+	*g = *gateway
+	return nil
+}
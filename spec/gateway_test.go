@@ -0,0 +1,33 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewayRef(t *testing.T) {
+	t.Run("parse & string", func(t *testing.T) {
+		g, err := ParseGatewayRef("gateway-system/public?http=web&https=websecure")
+		require.NoError(t, err)
+		require.Equal(t, GatewayRef{
+			Namespace:        "gateway-system",
+			Name:             "public",
+			HTTPSectionName:  "web",
+			HTTPSSectionName: "websecure",
+		}, *g)
+		require.Equal(t, "websecure", g.SectionName(true))
+		require.Equal(t, "gateway-system/public?http=web&https=websecure", g.String())
+
+		g, err = ParseGatewayRef("public")
+		require.NoError(t, err)
+		require.Equal(t, "public", g.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"", "a/b/c", "gateway-system/"} {
+			_, err := ParseGatewayRef(s)
+			require.Error(t, err, s)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	DefaultRegistry = "docker.io"
+	// namespace of official images in docker hub
+	DefaultRegistryNamespace = "library"
+	DefaultImageTag          = "latest"
+)
+
+var (
+	reImagePathComponent = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	reImageRegistry      = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?$`)
+	reImageTag           = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	reImageDigest        = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+)
+
+// ParseImageReference parses image reference
+//
+//	nginx
+//	nginx:1.25
+//	library/nginx:1.25
+//	docker.io/library/nginx:1.25
+//	registry.example.com:5000/team/app:1.0.0@sha256:<hex>
+//
+// the first component is registry when it contains '.' or ':', or is localhost.
+func ParseImageReference(s string) (*ImageReference, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing image")
+	}
+
+	ref := &ImageReference{}
+
+	name := s
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+
+		if !reImageDigest.MatchString(ref.Digest) {
+			return nil, fmt.Errorf("invalid image %q, invalid digest %q", s, ref.Digest)
+		}
+		if err := checkDigestLength(ref.Digest); err != nil {
+			return nil, fmt.Errorf("invalid image %q, %s", s, err)
+		}
+	}
+
+	// tag is after the last ':' which is not a part of registry
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i+1:], "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+
+		if !reImageTag.MatchString(ref.Tag) {
+			return nil, fmt.Errorf("invalid image %q, invalid tag %q", s, ref.Tag)
+		}
+	}
+
+	components := strings.Split(name, "/")
+
+	if len(components) > 1 && isImageRegistry(components[0]) {
+		ref.Registry = components[0]
+		components = components[1:]
+
+		if !reImageRegistry.MatchString(ref.Registry) {
+			return nil, fmt.Errorf("invalid image %q, invalid registry %q", s, ref.Registry)
+		}
+	}
+
+	for _, c := range components {
+		if !reImagePathComponent.MatchString(c) {
+			return nil, fmt.Errorf("invalid image %q, invalid repository %q", s, strings.Join(components, "/"))
+		}
+	}
+
+	ref.Repository = strings.Join(components, "/")
+
+	if len(ref.String()) > 255 {
+		return nil, fmt.Errorf("invalid image %q, should be no longer than 255", s)
+	}
+
+	return ref, nil
+}
+
+func isImageRegistry(component string) bool {
+	return strings.ContainsAny(component, ".:") || component == "localhost"
+}
+
+func checkDigestLength(digest string) error {
+	parts := strings.SplitN(digest, ":", 2)
+
+	switch parts[0] {
+	case "sha256":
+		if len(parts[1]) != 64 {
+			return fmt.Errorf("sha256 digest should be 64 hex characters")
+		}
+	case "sha512":
+		if len(parts[1]) != 128 {
+			return fmt.Errorf("sha512 digest should be 128 hex characters")
+		}
+	}
+
+	return nil
+}
+
+// openapi:strfmt image-reference
+type ImageReference struct {
+	// host[:port], empty for docker hub
+	Registry string
+	// path of repository, with namespace
+	Repository string
+	Tag        string
+	// <algorithm>:<hex>
+	Digest string
+}
+
+// Normalize returns the fully qualified reference,
+// docker.io as default registry, library as namespace of official images in docker hub
+// and latest as default tag when without digest
+func (r ImageReference) Normalize() ImageReference {
+	if r.Registry == "" || r.Registry == "index.docker.io" {
+		r.Registry = DefaultRegistry
+	}
+
+	if r.Registry == DefaultRegistry && !strings.Contains(r.Repository, "/") {
+		r.Repository = DefaultRegistryNamespace + "/" + r.Repository
+	}
+
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = DefaultImageTag
+	}
+
+	return r
+}
+
+// WithDigest returns a copy pinned by digest, tag will be kept for readability but ignored by runtime
+func (r ImageReference) WithDigest(digest string) (ImageReference, error) {
+	if !reImageDigest.MatchString(digest) {
+		return r, fmt.Errorf("invalid digest %q", digest)
+	}
+	if err := checkDigestLength(digest); err != nil {
+		return r, err
+	}
+	r.Digest = digest
+	return r, nil
+}
+
+// IsLatest returns true when image is tagged latest or untagged, and not pinned by digest
+func (r ImageReference) IsLatest() bool {
+	return r.Digest == "" && (r.Tag == "" || r.Tag == DefaultImageTag)
+}
+
+func (r ImageReference) String() string {
+	s := r.Repository
+	if r.Registry != "" {
+		s = r.Registry + "/" + s
+	}
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+func (r ImageReference) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *ImageReference) UnmarshalText(data []byte) error {
+	ref, err := ParseImageReference(string(data))
+	if err != nil {
+		return err
+	}
+	*r = *ref
+	return nil
+}
@@ -0,0 +1,109 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-courier/helmx/constants"
+	"github.com/stretchr/testify/require"
+)
+
+var testDigest = "sha256:" + strings.Repeat("a", 64)
+
+func TestImageReference(t *testing.T) {
+	t.Run("parse & normalize", func(t *testing.T) {
+		cases := []struct {
+			image      string
+			ref        ImageReference
+			normalized string
+		}{
+			{"nginx", ImageReference{Repository: "nginx"}, "docker.io/library/nginx:latest"},
+			{"nginx:1.25", ImageReference{Repository: "nginx", Tag: "1.25"}, "docker.io/library/nginx:1.25"},
+			{"bitnami/redis:7.0", ImageReference{Repository: "bitnami/redis", Tag: "7.0"}, "docker.io/bitnami/redis:7.0"},
+			{"index.docker.io/nginx", ImageReference{Registry: "index.docker.io", Repository: "nginx"}, "docker.io/library/nginx:latest"},
+			{"localhost/app", ImageReference{Registry: "localhost", Repository: "app"}, "localhost/app:latest"},
+			{
+				"registry.example.com:5000/team/app:1.0.0@" + testDigest,
+				ImageReference{Registry: "registry.example.com:5000", Repository: "team/app", Tag: "1.0.0", Digest: testDigest},
+				"registry.example.com:5000/team/app:1.0.0@" + testDigest,
+			},
+			{"app@" + testDigest, ImageReference{Repository: "app", Digest: testDigest}, "docker.io/library/app@" + testDigest},
+		}
+
+		for _, c := range cases {
+			ref, err := ParseImageReference(c.image)
+			require.NoError(t, err, c.image)
+			require.Equal(t, c.ref, *ref, c.image)
+			require.Equal(t, c.image, ref.String())
+			require.Equal(t, c.normalized, ref.Normalize().String(), c.image)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, image := range []string{
+			"",
+			"Nginx",
+			"/nginx",
+			"nginx:",
+			"nginx:-1",
+			"nginx@sha256:abc",
+			"nginx@" + strings.Repeat("a", 64),
+			"team//app",
+			"registry.example.com:port/app",
+		} {
+			_, err := ParseImageReference(image)
+			require.Error(t, err, image)
+		}
+	})
+
+	t.Run("digest pinning", func(t *testing.T) {
+		ref, _ := ParseImageReference("nginx:1.25")
+
+		pinned, err := ref.WithDigest(testDigest)
+		require.NoError(t, err)
+		require.Equal(t, "nginx:1.25@"+testDigest, pinned.String())
+
+		_, err = ref.WithDigest("sha256:xyz")
+		require.Error(t, err)
+	})
+
+	t.Run("pull policy", func(t *testing.T) {
+		for image, policy := range map[string]constants.PullPolicy{
+			"nginx":                      constants.PullAlways,
+			"nginx:latest":               constants.PullAlways,
+			"nginx:1.25":                 "",
+			"nginx:latest@" + testDigest: "",
+		} {
+			ref, err := ParseImageReference(image)
+			require.NoError(t, err)
+			require.Equal(t, policy, Image{}.ResolveImagePullPolicy(*ref), image)
+		}
+	})
+}
+
+func TestImage(t *testing.T) {
+	t.Run("default tag", func(t *testing.T) {
+		ref, err := Image{ImagePullSecret: &ImagePullSecret{}}.ImageReference(Project{Name: "helmx", Version: Version{Major: 1}}.DefaultImageTag())
+		require.NoError(t, err)
+		require.Equal(t, "helmx:1.0.0", ref.String())
+	})
+
+	t.Run("pinned by digest", func(t *testing.T) {
+		i := Image{Tag: "nginx", Digest: testDigest, ImagePullSecret: &ImagePullSecret{}}
+
+		ref, err := i.ImageReference("")
+		require.NoError(t, err)
+		require.Equal(t, "nginx@"+testDigest, ref.String())
+		require.Equal(t, constants.PullPolicy(""), i.ResolveImagePullPolicy(*ref))
+
+		i.Digest = "sha256:xyz"
+		_, err = i.ImageReference("")
+		require.Error(t, err)
+	})
+
+	t.Run("pull policy", func(t *testing.T) {
+		ref, _ := ParseImageReference("nginx")
+		require.Equal(t, constants.PullAlways, Image{}.ResolveImagePullPolicy(*ref))
+		require.Equal(t, constants.PullNever, Image{ImagePullPolicy: constants.PullNever}.ResolveImagePullPolicy(*ref))
+	})
+}
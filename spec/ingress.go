@@ -0,0 +1,125 @@
+package spec
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// IngressOptions are controller independent settings of ingress,
+// which are mapped to annotations by the profile of the ingress controller.
+type IngressOptions struct {
+	// profile of ingress controller, like nginx or traefik, ingressClassName as default
+	Controller string `json:"controller,omitempty" yaml:"controller,omitempty"`
+	// rewrite target of matched path
+	Rewrite string `json:"rewrite,omitempty" yaml:"rewrite,omitempty"`
+	// max size of request body, like 8m
+	BodySize string `json:"bodySize,omitempty" yaml:"bodySize,omitempty"`
+	// HTTP, HTTPS, GRPC or GRPCS, derived from the app protocol of the port when empty
+	BackendProtocol string `json:"backendProtocol,omitempty" yaml:"backendProtocol,omitempty"`
+	// middlewares of traefik, like default-redirect-https@kubernetescrd
+	Middlewares []string `json:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	// extra annotations, which take precedence over annotations of profile
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// Merge returns options with non-empty settings of src override
+func (o IngressOptions) Merge(src IngressOptions) IngressOptions {
+	if src.Controller != "" {
+		o.Controller = src.Controller
+	}
+	if src.Rewrite != "" {
+		o.Rewrite = src.Rewrite
+	}
+	if src.BodySize != "" {
+		o.BodySize = src.BodySize
+	}
+	if src.BackendProtocol != "" {
+		o.BackendProtocol = src.BackendProtocol
+	}
+	if len(src.Middlewares) > 0 {
+		o.Middlewares = src.Middlewares
+	}
+
+	if len(src.Annotations) > 0 {
+		annotations := map[string]string{}
+		for k, v := range o.Annotations {
+			annotations[k] = v
+		}
+		for k, v := range src.Annotations {
+			annotations[k] = v
+		}
+		o.Annotations = annotations
+	}
+
+	return o
+}
+
+// BackendProtocolOf returns the backend protocol of ingress by app protocol of port
+//
+//	grpc => GRPC, grpcs => GRPCS, https => HTTPS
+func BackendProtocolOf(appProtocol string) string {
+	switch strings.ToLower(appProtocol) {
+	case "grpc", "grpcs", "https":
+		return strings.ToUpper(appProtocol)
+	}
+	return ""
+}
+
+// parseIngressOptions parses options of ingress rule from query
+//
+//	?rewrite=/&bodySize=8m&backendProtocol=GRPC&middlewares=a,b&annotation=key=value
+func parseIngressOptions(query url.Values) (IngressOptions, error) {
+	o := IngressOptions{
+		Rewrite:         query.Get("rewrite"),
+		BodySize:        query.Get("bodySize"),
+		BackendProtocol: strings.ToUpper(query.Get("backendProtocol")),
+	}
+
+	if middlewares := query.Get("middlewares"); middlewares != "" {
+		o.Middlewares = strings.Split(middlewares, ",")
+	}
+
+	for _, annotation := range query["annotation"] {
+		kv := strings.SplitN(annotation, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return o, fmt.Errorf("invalid ingress annotation %q, should be key=value", annotation)
+		}
+		if o.Annotations == nil {
+			o.Annotations = map[string]string{}
+		}
+		o.Annotations[kv[0]] = kv[1]
+	}
+
+	return o, nil
+}
+
+func (o IngressOptions) values() url.Values {
+	values := url.Values{}
+
+	if o.Rewrite != "" {
+		values.Set("rewrite", o.Rewrite)
+	}
+	if o.BodySize != "" {
+		values.Set("bodySize", o.BodySize)
+	}
+	if o.BackendProtocol != "" {
+		values.Set("backendProtocol", o.BackendProtocol)
+	}
+	if len(o.Middlewares) > 0 {
+		values.Set("middlewares", strings.Join(o.Middlewares, ","))
+	}
+
+	keys := make([]string, 0, len(o.Annotations))
+	for k := range o.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		values.Add("annotation", k+"="+o.Annotations[k])
+	}
+
+	return values
+}
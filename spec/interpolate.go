@@ -0,0 +1,317 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+)
+
+func NewInterpolator(project *Project, envs Envs) *Interpolator {
+	return &Interpolator{
+		Project: project,
+		Envs:    envs,
+	}
+}
+
+// Interpolator resolves ${...} references in strings
+//
+//	${project.name}, ${project.feature}, ${project.version}, ${project.group}, ${project.description}, ${project.fullName}
+//	${KEY} value of env KEY declared in envs, or from LookupEnv when set
+//
+// ${KEY} not declared is kept as it is, like shell variables in command,
+// so rendering never depends on the environment of the renderer unless LookupEnv is set.
+//
+// $${...} is escaped as literal ${...}, $(KEY) is kept as it is for kubernetes dependent env vars,
+// and env from configmap, secret, field or resource is resolved as $(KEY).
+type Interpolator struct {
+	Project *Project
+	Envs    Envs
+	// opt-in lookup for keys not in envs, like os.LookupEnv
+	LookupEnv func(key string) (string, bool)
+}
+
+func (i *Interpolator) Interpolate(s string) (string, error) {
+	return i.interpolate(s, nil)
+}
+
+func (i *Interpolator) InterpolateEnvs(envs Envs) (Envs, error) {
+	if envs == nil {
+		return nil, nil
+	}
+
+	es := Envs{}
+
+	for k, v := range envs {
+		resolved, err := i.interpolate(v, []string{k})
+		if err != nil {
+			return nil, fmt.Errorf("env %s: %s", k, err)
+		}
+		es[k] = resolved
+	}
+
+	return es, nil
+}
+
+func (i *Interpolator) interpolate(s string, stack []string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	buf := &strings.Builder{}
+
+	for idx := 0; idx < len(s); {
+		if strings.HasPrefix(s[idx:], "$${") {
+			buf.WriteString("${")
+			idx += 3
+			continue
+		}
+
+		if strings.HasPrefix(s[idx:], "${") {
+			end := strings.Index(s[idx+2:], "}")
+			if end < 0 {
+				return "", fmt.Errorf("unclosed ${ in %q", s)
+			}
+
+			v, ok, err := i.resolve(strings.TrimSpace(s[idx+2:idx+2+end]), stack)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				v = s[idx : idx+2+end+1]
+			}
+
+			buf.WriteString(v)
+			idx += 2 + end + 1
+			continue
+		}
+
+		buf.WriteByte(s[idx])
+		idx++
+	}
+
+	return buf.String(), nil
+}
+
+// resolve returns false when ref is not declared, which should be kept as it is
+func (i *Interpolator) resolve(ref string, stack []string) (string, bool, error) {
+	if ref == "" {
+		return "", false, fmt.Errorf("empty reference ${}")
+	}
+
+	if strings.HasPrefix(ref, "project.") {
+		v, err := i.resolveProject(strings.TrimPrefix(ref, "project."))
+		return v, err == nil, err
+	}
+
+	for idx, key := range stack {
+		if key == ref {
+			return "", false, fmt.Errorf("cyclic reference %s", strings.Join(append(stack[idx:], ref), " -> "))
+		}
+	}
+
+	if v, ok := i.Envs[ref]; ok {
+		if isValueFrom, _ := IsValueFrom(v); isValueFrom {
+			return "$(" + ref + ")", true, nil
+		}
+		resolved, err := i.interpolate(v, append(stack, ref))
+		return resolved, err == nil, err
+	}
+
+	if i.LookupEnv != nil {
+		if v, ok := i.LookupEnv(ref); ok {
+			return v, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+func (i *Interpolator) resolveProject(field string) (string, error) {
+	if i.Project == nil {
+		return "", fmt.Errorf("undefined reference ${project.%s}, missing project", field)
+	}
+
+	switch field {
+	case "name":
+		return i.Project.Name, nil
+	case "feature":
+		return i.Project.Feature, nil
+	case "version":
+		return i.Project.Version.String(), nil
+	case "group":
+		return i.Project.Group, nil
+	case "description":
+		return i.Project.Description, nil
+	case "fullName":
+		return i.Project.FullName(), nil
+	}
+
+	return "", fmt.Errorf("undefined reference ${project.%s}", field)
+}
+
+func (i *Interpolator) interpolateStrings(values []string) ([]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	list := make([]string, len(values))
+
+	for idx := range values {
+		v, err := i.Interpolate(values[idx])
+		if err != nil {
+			return nil, err
+		}
+		list[idx] = v
+	}
+
+	return list, nil
+}
+
+func (i *Interpolator) interpolateStringMap(name string, values map[string]string) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	m := map[string]string{}
+	for k, v := range values {
+		resolved, err := i.Interpolate(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %s", name, k, err)
+		}
+		m[k] = resolved
+	}
+
+	return m, nil
+}
+
+// Interpolate returns a copy of the spec with ${...} resolved in
+// envs, labels, annotations, images, working dirs, commands, args, ingresses and tls hosts.
+//
+// Container envs are resolved with the spec envs merged in, while
+// everything else is resolved against the spec envs only.
+func (s Spec) Interpolate() (Spec, error) {
+	i := NewInterpolator(s.Project, s.Envs)
+
+	envs, err := i.InterpolateEnvs(s.Envs)
+	if err != nil {
+		return s, err
+	}
+
+	if s.Labels, err = i.interpolateStringMap("label", s.Labels); err != nil {
+		return s, err
+	}
+
+	if s.Annotations, err = i.interpolateStringMap("annotation", s.Annotations); err != nil {
+		return s, err
+	}
+
+	if s.PodAnnotations, err = i.interpolateStringMap("pod annotation", s.PodAnnotations); err != nil {
+		return s, err
+	}
+
+	if s.Metadata != nil {
+		metadata := map[string]ObjectMeta{}
+		for kind, meta := range s.Metadata {
+			if meta.Labels, err = i.interpolateStringMap(kind+" label", meta.Labels); err != nil {
+				return s, err
+			}
+			if meta.Annotations, err = i.interpolateStringMap(kind+" annotation", meta.Annotations); err != nil {
+				return s, err
+			}
+			metadata[kind] = meta
+		}
+		s.Metadata = metadata
+	}
+
+	if s.Service != nil {
+		service := *s.Service
+
+		if err := service.Pod.interpolate(s.Project, s.Envs); err != nil {
+			return s, fmt.Errorf("service: %s", err)
+		}
+
+		ingresses := make([]IngressRule, len(service.Ingresses))
+		for idx, r := range service.Ingresses {
+			if r.Host, err = i.Interpolate(r.Host); err != nil {
+				return s, fmt.Errorf("service ingress %s: %s", r, err)
+			}
+			if r.Path, err = i.Interpolate(r.Path); err != nil {
+				return s, fmt.Errorf("service ingress %s: %s", r, err)
+			}
+			ingresses[idx] = r
+		}
+		service.Ingresses = ingresses
+
+		tls := make([]IngressTLS, len(service.TLS))
+		for idx, t := range service.TLS {
+			if t.Hosts, err = i.interpolateStrings(t.Hosts); err != nil {
+				return s, fmt.Errorf("service tls %s: %s", t, err)
+			}
+			tls[idx] = t
+		}
+		service.TLS = tls
+
+		s.Service = &service
+	}
+
+	if s.Jobs != nil {
+		jobs := map[string]Job{}
+		for name, job := range s.Jobs {
+			if err := job.Pod.interpolate(s.Project, s.Envs); err != nil {
+				return s, fmt.Errorf("job %s: %s", name, err)
+			}
+			jobs[name] = job
+		}
+		s.Jobs = jobs
+	}
+
+	s.Envs = envs
+
+	return s, nil
+}
+
+func (p *Pod) interpolate(project *Project, envs Envs) error {
+	if err := p.Container.interpolate(project, envs); err != nil {
+		return err
+	}
+
+	if p.Initials != nil {
+		initials := make([]Container, len(p.Initials))
+		for idx, c := range p.Initials {
+			if err := c.interpolate(project, envs); err != nil {
+				return fmt.Errorf("initials[%d]: %s", idx, err)
+			}
+			initials[idx] = c
+		}
+		p.Initials = initials
+	}
+
+	return nil
+}
+
+func (c *Container) interpolate(project *Project, envs Envs) error {
+	i := NewInterpolator(project, c.Envs.Merge(envs))
+
+	var err error
+
+	if c.Tag, err = i.Interpolate(c.Tag); err != nil {
+		return fmt.Errorf("image: %s", err)
+	}
+
+	if c.WorkingDir, err = i.Interpolate(c.WorkingDir); err != nil {
+		return fmt.Errorf("workingDir: %s", err)
+	}
+
+	if c.Command, err = i.interpolateStrings(c.Command); err != nil {
+		return fmt.Errorf("command: %s", err)
+	}
+
+	if c.Args, err = i.interpolateStrings(c.Args); err != nil {
+		return fmt.Errorf("args: %s", err)
+	}
+
+	if c.Envs, err = i.InterpolateEnvs(c.Envs); err != nil {
+		return err
+	}
+
+	return nil
+}
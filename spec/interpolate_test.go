@@ -0,0 +1,119 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolator(t *testing.T) {
+	project := &Project{
+		Name:    "helmx",
+		Feature: "test",
+		Version: Version{Major: 1, Minor: 2, Patch: 3},
+	}
+
+	i := NewInterpolator(project, Envs{
+		"HOST":     "${project.fullName}.svc",
+		"URL":      "http://${HOST}:80",
+		"PASSWORD": "####secretName.password.false####",
+		"A":        "${B}",
+		"B":        "${A}",
+	})
+	i.LookupEnv = func(key string) (string, bool) {
+		if key == "HOME" {
+			return "/root", true
+		}
+		return "", false
+	}
+
+	t.Run("project", func(t *testing.T) {
+		v, err := i.Interpolate("docker.io/helmx:${project.version}")
+		require.NoError(t, err)
+		require.Equal(t, "docker.io/helmx:1.2.3", v)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		v, err := i.Interpolate("${URL}/api")
+		require.NoError(t, err)
+		require.Equal(t, "http://helmx--test.svc:80/api", v)
+	})
+
+	t.Run("env from secret as dependent env var", func(t *testing.T) {
+		v, err := i.Interpolate("user:${PASSWORD}")
+		require.NoError(t, err)
+		require.Equal(t, "user:$(PASSWORD)", v)
+	})
+
+	t.Run("process env", func(t *testing.T) {
+		v, err := i.Interpolate("${HOME}/.kube")
+		require.NoError(t, err)
+		require.Equal(t, "/root/.kube", v)
+	})
+
+	t.Run("escape and passthrough", func(t *testing.T) {
+		v, err := i.Interpolate("$${HOME} $(POD_IP) $HOME")
+		require.NoError(t, err)
+		require.Equal(t, "${HOME} $(POD_IP) $HOME", v)
+	})
+
+	t.Run("cyclic reference", func(t *testing.T) {
+		_, err := i.Interpolate("${A}")
+		require.EqualError(t, err, "cyclic reference A -> B -> A")
+	})
+
+	t.Run("undefined reference", func(t *testing.T) {
+		_, err := i.Interpolate("${project.unknown}")
+		require.Error(t, err)
+
+		_, err = i.Interpolate("${HOME")
+		require.Error(t, err)
+	})
+
+	t.Run("undeclared reference kept", func(t *testing.T) {
+		v, err := i.Interpolate("echo ${UNKNOWN} ${ POD_IP } ${URL}")
+		require.NoError(t, err)
+		require.Equal(t, "echo ${UNKNOWN} ${ POD_IP } http://helmx--test.svc:80", v)
+
+		v, err = NewInterpolator(project, nil).Interpolate("${HOME}/.kube")
+		require.NoError(t, err)
+		require.Equal(t, "${HOME}/.kube", v)
+	})
+
+	t.Run("envs", func(t *testing.T) {
+		envs, err := i.InterpolateEnvs(Envs{"LINK": "${URL}", "SELF": "x"})
+		require.NoError(t, err)
+		require.Equal(t, Envs{"LINK": "http://helmx--test.svc:80", "SELF": "x"}, envs)
+
+		_, err = i.InterpolateEnvs(Envs{"A": "${B}"})
+		require.EqualError(t, err, "env A: cyclic reference A -> B -> A")
+	})
+}
+
+func TestSpecInterpolate(t *testing.T) {
+	s := Spec{
+		Project: &Project{Name: "helmx", Version: Version{Major: 1}},
+		Envs:    Envs{"DOMAIN": "example.com"},
+		Service: &Service{},
+	}
+	s.Service.Tag = "helmx:${project.version}"
+	s.Service.Envs = Envs{"BASE_URL": "https://${DOMAIN}"}
+	s.Service.Command = []string{"sh", "-c", "echo ${HOME} ${POD_IP} ${DOMAIN}"}
+	s.Service.Ingresses = []IngressRule{{Host: "${project.name}.${DOMAIN}"}}
+	s.Annotations = map[string]string{"owner": "${project.name}@${DOMAIN}"}
+	s.Metadata = map[string]ObjectMeta{KindService: {Labels: map[string]string{"version": "${project.version}"}}}
+
+	resolved, err := s.Interpolate()
+	require.NoError(t, err)
+
+	require.Equal(t, "helmx:1.0.0", resolved.Service.Tag)
+	require.Equal(t, "https://example.com", resolved.Service.Envs["BASE_URL"])
+	require.Equal(t, "echo ${HOME} ${POD_IP} example.com", resolved.Service.Command[2])
+	require.Equal(t, "helmx.example.com", resolved.Service.Ingresses[0].Host)
+	require.Equal(t, "helmx@example.com", resolved.Annotations["owner"])
+	require.Equal(t, "1.0.0", resolved.Metadata[KindService].Labels["version"])
+
+	// source spec untouched
+	require.Equal(t, "helmx:${project.version}", s.Service.Tag)
+	require.Equal(t, "https://${DOMAIN}", s.Service.Envs["BASE_URL"])
+}
@@ -0,0 +1,68 @@
+package spec
+
+// kinds of generated objects, as keys of Spec.Metadata
+const (
+	KindPod             = "pod"
+	KindNamespace       = "namespace"
+	KindService         = "service"
+	KindDeployment      = "deployment"
+	KindJob             = "job"
+	KindCronJob         = "cronJob"
+	KindIngress         = "ingress"
+	KindHTTPRoute       = "httpRoute"
+	KindNetworkPolicy   = "networkPolicy"
+	KindExternalService = "externalService"
+	KindEndpointSlice   = "endpointSlice"
+	KindServiceAccount  = "serviceAccount"
+	KindRole            = "role"
+	KindRoleBinding     = "roleBinding"
+	KindSecret          = "secret"
+)
+
+// ObjectMeta is labels and annotations of generated object
+type ObjectMeta struct {
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// Merge returns a copy with labels and annotations of the given one take precedence
+func (m ObjectMeta) Merge(meta ObjectMeta) ObjectMeta {
+	return ObjectMeta{
+		Labels:      mergeStringMap(m.Labels, meta.Labels),
+		Annotations: mergeStringMap(m.Annotations, meta.Annotations),
+	}
+}
+
+// ObjectMetaOf returns labels and annotations of object of the kind,
+// recommended labels of project and spec labels and annotations, overridden by Spec.Metadata[kind]
+func (s Spec) ObjectMetaOf(kind string) ObjectMeta {
+	meta := ObjectMeta{Labels: s.labels(), Annotations: s.Annotations}
+	return meta.Merge(s.Metadata[kind])
+}
+
+// PodMeta returns labels and annotations of pod templates,
+// recommended labels of project and spec labels and pod annotations, overridden by Spec.Metadata["pod"]
+func (s Spec) PodMeta() ObjectMeta {
+	meta := ObjectMeta{Labels: s.labels(), Annotations: s.PodAnnotations}
+	return meta.Merge(s.Metadata[KindPod])
+}
+
+func (s Spec) labels() map[string]string {
+	if s.Project == nil {
+		return s.Labels
+	}
+	return mergeStringMap(s.Project.RecommendedLabels(), s.Labels)
+}
+
+func mergeStringMap(maps ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
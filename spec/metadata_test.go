@@ -0,0 +1,89 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectMeta(t *testing.T) {
+	s := Spec{
+		Labels:         map[string]string{"team": "infra"},
+		Annotations:    map[string]string{"owner": "infra"},
+		PodAnnotations: map[string]string{"prometheus.io/scrape": "true"},
+		Metadata: map[string]ObjectMeta{
+			KindService: {
+				Labels:      map[string]string{"team": "web"},
+				Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+			},
+		},
+	}
+
+	t.Run("of kind", func(t *testing.T) {
+		require.Equal(t, ObjectMeta{
+			Labels:      map[string]string{"team": "infra"},
+			Annotations: map[string]string{"owner": "infra"},
+		}, s.ObjectMetaOf(KindDeployment))
+	})
+
+	t.Run("of kind with overrides", func(t *testing.T) {
+		require.Equal(t, ObjectMeta{
+			Labels: map[string]string{"team": "web"},
+			Annotations: map[string]string{
+				"owner": "infra",
+				"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+			},
+		}, s.ObjectMetaOf(KindService))
+	})
+
+	t.Run("pod", func(t *testing.T) {
+		require.Equal(t, ObjectMeta{
+			Labels:      map[string]string{"team": "infra"},
+			Annotations: map[string]string{"prometheus.io/scrape": "true"},
+		}, s.PodMeta())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Equal(t, ObjectMeta{}, Spec{}.ObjectMetaOf(KindService))
+	})
+}
+
+func TestProjectRecommendedLabels(t *testing.T) {
+	t.Run("full", func(t *testing.T) {
+		p := Project{Name: "helmx", Feature: "test", Group: "infra", Version: Version{Major: 1, Minor: 2}}
+
+		require.Equal(t, map[string]string{
+			"app.kubernetes.io/name":       "helmx",
+			"app.kubernetes.io/instance":   "helmx--test",
+			"app.kubernetes.io/version":    "1.2.0",
+			"app.kubernetes.io/component":  "test",
+			"app.kubernetes.io/part-of":    "infra",
+			"app.kubernetes.io/managed-by": "helmx",
+		}, p.RecommendedLabels())
+	})
+
+	t.Run("without feature and group", func(t *testing.T) {
+		p := Project{Name: "helmx", Version: Version{Major: 1}}
+
+		require.Equal(t, map[string]string{
+			"app.kubernetes.io/name":       "helmx",
+			"app.kubernetes.io/instance":   "helmx",
+			"app.kubernetes.io/version":    "1.0.0",
+			"app.kubernetes.io/managed-by": "helmx",
+		}, p.RecommendedLabels())
+	})
+
+	t.Run("invalid label value", func(t *testing.T) {
+		require.Equal(t, "1.0.0_build", labelValue("1.0.0+build"))
+		require.Equal(t, "v1", labelValue("~v1~"))
+	})
+
+	t.Run("spec labels take precedence", func(t *testing.T) {
+		s := Spec{
+			Project: &Project{Name: "helmx"},
+			Labels:  map[string]string{"app.kubernetes.io/part-of": "platform"},
+		}
+		require.Equal(t, "platform", s.ObjectMetaOf(KindService).Labels["app.kubernetes.io/part-of"])
+		require.Equal(t, "helmx", s.PodMeta().Labels["app.kubernetes.io/name"])
+	})
+}
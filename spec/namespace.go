@@ -0,0 +1,169 @@
+package spec
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type PodSecurityLevel string
+
+const (
+	PodSecurityLevelPrivileged PodSecurityLevel = "privileged"
+	PodSecurityLevelBaseline   PodSecurityLevel = "baseline"
+	PodSecurityLevelRestricted PodSecurityLevel = "restricted"
+)
+
+// modes of Pod Security admission, as suffix of label pod-security.kubernetes.io/<mode>
+var PodSecurityModes = []string{"enforce", "audit", "warn"}
+
+var reDNS1123Label = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ParseNamespace parses namespace
+//
+//	helmx
+//	helmx?create
+//	helmx?create&podSecurity=restricted
+//	helmx?create&enforce=baseline&warn=restricted
+//
+// with create, the Namespace object will be rendered by toKubeNamespace,
+// podSecurity sets the level of all modes of Pod Security admission, enforce, audit and warn for each mode.
+func ParseNamespace(s string) (*Namespace, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing namespace")
+	}
+
+	ns := &Namespace{}
+
+	if i := strings.Index(s, "?"); i >= 0 {
+		query, err := url.ParseQuery(s[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace %q: %s", s, err)
+		}
+
+		_, ns.Create = query["create"]
+
+		if level := query.Get("podSecurity"); level != "" {
+			for _, mode := range PodSecurityModes {
+				if err := ns.setPodSecurity(mode, level); err != nil {
+					return nil, fmt.Errorf("invalid namespace %q: %s", s, err)
+				}
+			}
+		}
+
+		for _, mode := range PodSecurityModes {
+			if level := query.Get(mode); level != "" {
+				if err := ns.setPodSecurity(mode, level); err != nil {
+					return nil, fmt.Errorf("invalid namespace %q: %s", s, err)
+				}
+			}
+		}
+
+		s = s[:i]
+	}
+
+	if len(s) > 63 || !reDNS1123Label.MatchString(s) {
+		return nil, fmt.Errorf("invalid namespace %q, should be a DNS-1123 label", s)
+	}
+
+	ns.Name = s
+
+	return ns, nil
+}
+
+// openapi:strfmt namespace
+type Namespace struct {
+	Name string
+	// render Namespace object
+	Create bool
+	// levels of Pod Security admission by mode
+	PodSecurity map[string]PodSecurityLevel
+}
+
+func (ns *Namespace) setPodSecurity(mode string, level string) error {
+	switch l := PodSecurityLevel(level); l {
+	case PodSecurityLevelPrivileged, PodSecurityLevelBaseline, PodSecurityLevelRestricted:
+		if ns.PodSecurity == nil {
+			ns.PodSecurity = map[string]PodSecurityLevel{}
+		}
+		ns.PodSecurity[mode] = l
+		return nil
+	}
+	return fmt.Errorf("unknown pod security level %q", level)
+}
+
+// PodSecurityLabels returns labels pod-security.kubernetes.io/<mode> of Pod Security admission
+func (ns Namespace) PodSecurityLabels() map[string]string {
+	if len(ns.PodSecurity) == 0 {
+		return nil
+	}
+
+	labels := map[string]string{}
+	for mode, level := range ns.PodSecurity {
+		labels["pod-security.kubernetes.io/"+mode] = string(level)
+	}
+	return labels
+}
+
+func (ns Namespace) String() string {
+	params := make([]string, 0)
+
+	if ns.Create {
+		params = append(params, "create")
+	}
+
+	if level, ok := ns.podSecurityOfAllModes(); ok {
+		params = append(params, "podSecurity="+string(level))
+	} else {
+		for _, mode := range PodSecurityModes {
+			if level, ok := ns.PodSecurity[mode]; ok {
+				params = append(params, mode+"="+string(level))
+			}
+		}
+	}
+
+	if len(params) == 0 {
+		return ns.Name
+	}
+
+	return ns.Name + "?" + strings.Join(params, "&")
+}
+
+func (ns Namespace) podSecurityOfAllModes() (PodSecurityLevel, bool) {
+	level := ns.PodSecurity[PodSecurityModes[0]]
+	if level == "" {
+		return "", false
+	}
+	for _, mode := range PodSecurityModes[1:] {
+		if ns.PodSecurity[mode] != level {
+			return "", false
+		}
+	}
+	return level, true
+}
+
+func (ns Namespace) MarshalText() ([]byte, error) {
+	return []byte(ns.String()), nil
+}
+
+func (ns *Namespace) UnmarshalText(data []byte) error {
+	namespace, err := ParseNamespace(string(data))
+	if err != nil {
+		return err
+	}
+	*ns = *namespace
+	return nil
+}
+
+// ResolveNamespace returns namespace of generated objects,
+// namespace of target takes precedence, empty for the namespace of current context
+func (s Spec) ResolveNamespace() string {
+	if s.Target != nil && s.Target.Namespace != nil {
+		return s.Target.Namespace.Name
+	}
+	if s.Namespace != nil {
+		return s.Namespace.Name
+	}
+	return ""
+}
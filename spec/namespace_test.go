@@ -0,0 +1,59 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestNamespace(t *testing.T) {
+	t.Run("parse & string", func(t *testing.T) {
+		ns, err := ParseNamespace("helmx")
+		require.NoError(t, err)
+		require.Equal(t, Namespace{Name: "helmx"}, *ns)
+		require.Equal(t, "helmx", ns.String())
+
+		ns, err = ParseNamespace("helmx?create&podSecurity=restricted")
+		require.NoError(t, err)
+		require.True(t, ns.Create)
+		require.Equal(t, map[string]string{
+			"pod-security.kubernetes.io/enforce": "restricted",
+			"pod-security.kubernetes.io/audit":   "restricted",
+			"pod-security.kubernetes.io/warn":    "restricted",
+		}, ns.PodSecurityLabels())
+		require.Equal(t, "helmx?create&podSecurity=restricted", ns.String())
+
+		ns, err = ParseNamespace("helmx?create&podSecurity=restricted&enforce=baseline")
+		require.NoError(t, err)
+		require.Equal(t, PodSecurityLevelBaseline, ns.PodSecurity["enforce"])
+		require.Equal(t, "helmx?create&enforce=baseline&audit=restricted&warn=restricted", ns.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"", "Helmx", "helmx_test", "helmx?podSecurity=strict"} {
+			_, err := ParseNamespace(s)
+			require.Error(t, err, s)
+		}
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		require.Equal(t, "", Spec{}.ResolveNamespace())
+
+		s := Spec{Namespace: &Namespace{Name: "helmx"}}
+		require.Equal(t, "helmx", s.ResolveNamespace())
+
+		s.Target = &Target{Namespace: &Namespace{Name: "helmx-staging"}}
+		require.Equal(t, "helmx-staging", s.ResolveNamespace())
+	})
+
+	t.Run("target", func(t *testing.T) {
+		target := Target{}
+		require.NoError(t, yaml.Unmarshal([]byte("namespace: helmx-staging"), &target))
+		require.Equal(t, "helmx-staging", target.Namespace.Name)
+
+		for _, s := range []string{"Helmx", "helmx_staging", "helmx/staging"} {
+			require.Error(t, yaml.Unmarshal([]byte("namespace: "+s), &Target{}), s)
+		}
+	})
+}
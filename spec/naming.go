@@ -0,0 +1,67 @@
+package spec
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+const (
+	// max length of DNS-1123 label
+	MaxNameLength = 63
+	// max length of name of CronJob, 11 characters are appended to the name of Jobs created by it
+	MaxCronJobNameLength = 52
+	// min length of truncated name, one alphanumeric, '-' and hash suffix
+	MinNameLength = 10
+)
+
+var reInvalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// SafeName converts name to DNS-1123 label, lowercase alphanumeric or '-',
+// and beginning and ending with alphanumeric.
+//
+// name longer than maxLength is truncated with a short hash suffix of the original name,
+// so the result is stable and long names with the same prefix won't conflict.
+// maxLength less than MinNameLength is treated as MinNameLength.
+//
+// name without any valid char is converted to the hash of it.
+func SafeName(name string, maxLength int) string {
+	if maxLength < MinNameLength {
+		maxLength = MinNameLength
+	}
+
+	safe := strings.Trim(reInvalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if safe == "" && name != "" {
+		return nameHash(name)
+	}
+	if len(safe) <= maxLength {
+		return safe
+	}
+
+	hash := nameHash(name)
+
+	return strings.TrimRight(safe[:maxLength-len(hash)-1], "-") + "-" + hash
+}
+
+// JoinName joins parts with "--" as SafeName no longer than MaxNameLength, empty parts are ignored
+func JoinName(parts ...string) string {
+	return JoinNameWithMaxLength(MaxNameLength, parts...)
+}
+
+// JoinNameWithMaxLength joins parts with "--" as SafeName no longer than maxLength, empty parts are ignored
+func JoinNameWithMaxLength(maxLength int, parts ...string) string {
+	nonEmptyParts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			nonEmptyParts = append(nonEmptyParts, p)
+		}
+	}
+	return SafeName(strings.Join(nonEmptyParts, "--"), maxLength)
+}
+
+func nameHash(name string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
@@ -0,0 +1,53 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeName(t *testing.T) {
+	t.Run("sanitize", func(t *testing.T) {
+		require.Equal(t, "helmx--feature-add-login", JoinName("helmx", "Feature/Add_Login"))
+		require.Equal(t, "helmx", JoinName("helmx", ""))
+		require.Equal(t, "wait-for-mysql-db-3306", SafeName("wait-for-mysql.db-3306", MaxNameLength))
+		require.Equal(t, "a-b", SafeName("-a.b-", MaxNameLength))
+	})
+
+	t.Run("truncate with hash", func(t *testing.T) {
+		feature := "feature-" + strings.Repeat("x", 60)
+
+		name := JoinName("helmx", feature)
+		require.Len(t, name, MaxNameLength)
+		require.True(t, strings.HasPrefix(name, "helmx--feature-xxx"))
+		require.Equal(t, name, JoinName("helmx", feature))
+		require.NotEqual(t, name, JoinName("helmx", feature+"y"))
+
+		require.Len(t, JoinNameWithMaxLength(MaxCronJobNameLength, "helmx", feature, "cron"), MaxCronJobNameLength)
+	})
+
+	t.Run("max length too small", func(t *testing.T) {
+		name := SafeName("helmx-"+strings.Repeat("x", 20), 5)
+		require.Len(t, name, MinNameLength)
+		require.True(t, strings.HasPrefix(name, "h-"))
+
+		require.Len(t, JoinNameWithMaxLength(0, "helmx", "test"), MinNameLength)
+		require.Equal(t, "a", SafeName("a", 1))
+	})
+
+	t.Run("fallback to hash", func(t *testing.T) {
+		name := SafeName("___", MaxNameLength)
+		require.Len(t, name, 8)
+		require.Equal(t, name, SafeName("___", MaxNameLength))
+		require.NotEqual(t, name, SafeName("...", MaxNameLength))
+
+		require.Equal(t, "", SafeName("", MaxNameLength))
+	})
+
+	t.Run("full name of project", func(t *testing.T) {
+		require.Equal(t, "helmx--test", Project{Name: "helmx", Feature: "test"}.FullName())
+		require.Equal(t, "helmx--feat-x", Project{Name: "helmx", Feature: "feat/X"}.FullName())
+		require.Len(t, Project{Name: "helmx", Feature: strings.Repeat("x", 100)}.FullName(), MaxNameLength)
+	})
+}
@@ -3,10 +3,12 @@ package spec
 import (
     "fmt"
     "net/url"
+    "regexp"
     "strconv"
     "strings"
 
     "github.com/go-courier/helmx/constants"
+    "github.com/go-courier/helmx/kubetypes"
 )
 
 func ParsePort(s string) (*Port, error) {
@@ -18,6 +20,7 @@ func ParsePort(s string) (*Port, error) {
     port := uint16(0)
     targetPort := uint16(0)
     protocol := ""
+    isLoadBalancer := false
     isNodePort := false
     nodePort := uint16(0)
 
@@ -27,11 +30,20 @@ func ParsePort(s string) (*Port, error) {
         protocol = strings.ToLower(parts[1])
     }
 
-    if s[0] == '!' {
+    if s != "" && s[0] == '^' {
+        isLoadBalancer = true
+        s = s[1:]
+    }
+
+    if s != "" && s[0] == '!' {
         isNodePort = true
         s = s[1:]
     }
 
+    if s == "" {
+        return nil, fmt.Errorf("missing port")
+    }
+
     ports := strings.Split(s, ":")
     portStr := ports[0]
     appProtocolAndPort := strings.Split(portStr, "-")
@@ -54,9 +66,6 @@ func ParsePort(s string) (*Port, error) {
         if isNodePort {
             nodePort = uint16(p)
             port = uint16(tp)
-            if nodePort < 20000 || nodePort > 40000 {
-                return nil, fmt.Errorf("invalid value: %d: provided port is not in the valid range. The range of valid ports is 20000-40000", port)
-            }
         } else {
             port = uint16(p)
         }
@@ -67,29 +76,138 @@ func ParsePort(s string) (*Port, error) {
     }
 
     return &Port{
-        AppProtocol:   appProtocol,
-        NodePort:      nodePort,
-        Port:          port,
-        IsNodePort:    isNodePort,
-        ContainerPort: targetPort,
-        Protocol:      constants.Protocol(strings.ToUpper(protocol)),
+        AppProtocol:    appProtocol,
+        NodePort:       nodePort,
+        Port:           port,
+        IsNodePort:     isNodePort,
+        IsLoadBalancer: isLoadBalancer,
+        ContainerPort:  targetPort,
+        Protocol:       constants.Protocol(strings.ToUpper(protocol)),
     }, nil
 }
 
 // openapi:strfmt port
 type Port struct {
-    AppProtocol   string
-    NodePort      uint16
-    Port          uint16
-    IsNodePort    bool
-    ContainerPort uint16
-    Protocol      constants.Protocol
+    AppProtocol string
+    NodePort    uint16
+    Port        uint16
+    IsNodePort  bool
+    // exposed by load balancer
+    IsLoadBalancer bool
+    ContainerPort  uint16
+    Protocol       constants.Protocol
+}
+
+// ServicePortName returns name of service port,
+// <appProtocol>-<port> or np-<appProtocol>-<nodePort> for node port, http as default app protocol,
+// non-TCP port named as <appProtocol>-<port>-<protocol>, or <protocol>-<port> when without app protocol or longer than 15
+func (s Port) ServicePortName() string {
+    if s.IsNodePort && s.NodePort != 0 {
+        return "np-" + s.portName(s.NodePort, 15-len("np-"))
+    }
+    return s.portName(s.Port, 15)
+}
+
+// ContainerPortName returns name of container port <appProtocol>-<containerPort> as ServicePortName,
+// empty when the name is not a valid port name, like longer than 15.
+func (s Port) ContainerPortName() string {
+    name := s.portName(s.ContainerPort, 15)
+    if !IsPortName(name) {
+        return ""
+    }
+    return name
+}
+
+func (s Port) portName(port uint16, maxLength int) string {
+    p := strconv.FormatUint(uint64(port), 10)
+
+    protocol := ""
+    if s.Protocol != "" && s.Protocol != constants.ProtocolTCP {
+        protocol = strings.ToLower(string(s.Protocol))
+    }
+
+    if protocol == "" {
+        appProtocol := s.AppProtocol
+        if appProtocol == "" {
+            appProtocol = "http"
+        }
+        return appProtocol + "-" + p
+    }
+
+    if s.AppProtocol != "" {
+        if name := s.AppProtocol + "-" + p + "-" + protocol; len(name) <= maxLength {
+            return name
+        }
+    }
+
+    return protocol + "-" + p
+}
+
+// containerPortKey returns <containerPort>/<protocol>, TCP as default protocol
+func (s Port) containerPortKey() string {
+    protocol := s.Protocol
+    if protocol == "" {
+        protocol = constants.ProtocolTCP
+    }
+    return fmt.Sprintf("%d/%s", s.ContainerPort, protocol)
+}
+
+var rePortName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+var reHasLetter = regexp.MustCompile(`[a-z]`)
+
+// IsPortName checks name of port, which should be an IANA_SVC_NAME
+// with lowercase letters, digits and '-', at least one letter, and no longer than 15
+func IsPortName(name string) bool {
+    return len(name) <= 15 &&
+        rePortName.MatchString(name) &&
+        reHasLetter.MatchString(name) &&
+        !strings.Contains(name, "--")
+}
+
+// parseURLWithPortName parses url which port could be a port name, like http://:http-80/healthz
+func parseURLWithPortName(s string) (*url.URL, string, error) {
+    i := strings.Index(s, "://")
+    if i < 0 {
+        u, err := url.Parse(s)
+        return u, "", err
+    }
+
+    rest := s[i+3:]
+
+    end := strings.IndexAny(rest, "/?#")
+    if end < 0 {
+        end = len(rest)
+    }
+
+    authority := rest[:end]
+
+    j := strings.LastIndex(authority, ":")
+    if j < 0 || strings.Contains(authority[j:], "]") {
+        u, err := url.Parse(s)
+        return u, "", err
+    }
+
+    port := authority[j+1:]
+    if _, err := strconv.ParseUint(port, 10, 16); port == "" || err == nil {
+        u, err := url.Parse(s)
+        return u, "", err
+    }
+
+    if !IsPortName(port) {
+        return nil, "", fmt.Errorf("invalid port name %q", port)
+    }
+
+    u, err := url.Parse(s[:i+3] + authority[:j] + rest[end:])
+    return u, port, err
 }
 
 func (s Port) String() string {
     v := ""
+    if s.IsLoadBalancer {
+        v = "^"
+    }
     if s.IsNodePort {
-        v = "!"
+        v += "!"
     }
 
     if s.AppProtocol != "" {
@@ -142,15 +260,48 @@ func ParseIngressRule(s string) (*IngressRule, error) {
         return nil, fmt.Errorf("invalid ingress rule")
     }
 
-    u, err := url.Parse(s)
+    var backend *IngressBackend
+
+    if parts := strings.Split(s, "->"); len(parts) == 2 {
+        b, err := ParseIngressBackend(strings.TrimSpace(parts[1]))
+        if err != nil {
+            return nil, err
+        }
+        backend = b
+        s = strings.TrimSpace(parts[0])
+    } else if len(parts) > 2 {
+        return nil, fmt.Errorf("invalid ingress rule %q", s)
+    }
+
+    u, portName, err := parseURLWithPortName(s)
     if err != nil {
         return nil, err
     }
 
     r := &IngressRule{
-        Scheme: u.Scheme,
-        Host:   u.Hostname(),
-        Path:   u.Path,
+        PortName: portName,
+        Scheme:   u.Scheme,
+        Host:     u.Hostname(),
+        Path:     u.Path,
+        Backend:  backend,
+    }
+
+    if strings.Contains(strings.TrimPrefix(r.Host, "*."), "*") && r.Host != "*" {
+        return nil, fmt.Errorf("invalid ingress host %q, wildcard should be the first label", r.Host)
+    }
+
+    if pathType := u.Query().Get("pathType"); pathType != "" {
+        switch kubetypes.PathType(pathType) {
+        case kubetypes.PathTypeExact, kubetypes.PathTypePrefix, kubetypes.PathTypeImplementationSpecific:
+            r.PathType = kubetypes.PathType(pathType)
+        default:
+            return nil, fmt.Errorf("invalid ingress path type %q", pathType)
+        }
+    }
+
+    r.Options, err = parseIngressOptions(u.Query())
+    if err != nil {
+        return nil, err
     }
 
     if r.Scheme == "" {
@@ -158,9 +309,11 @@ func ParseIngressRule(s string) (*IngressRule, error) {
     }
 
     p := u.Port()
-    if p == "" {
+    switch {
+    case portName != "":
+    case p == "":
         r.Port = 80
-    } else {
+    default:
         port, _ := strconv.ParseUint(p, 10, 16)
         r.Port = uint16(port)
     }
@@ -174,6 +327,19 @@ type IngressRule struct {
     Host   string
     Path   string
     Port   uint16
+    // name of service port, like http-80, takes precedence over Port
+    PortName string
+    // Exact, Prefix or ImplementationSpecific, Prefix as default
+    PathType kubetypes.PathType
+    // options of the rule, override options of service
+    Options IngressOptions
+    // backend of the rule, the service itself on Port as default
+    Backend *IngressBackend
+}
+
+// IsWildcardHost returns true when the rule matches all hosts
+func (r IngressRule) IsWildcardHost() bool {
+    return r.Host == "*" || r.Host == ""
 }
 
 func (r IngressRule) String() string {
@@ -184,11 +350,27 @@ func (r IngressRule) String() string {
         r.Port = 80
     }
 
-    return (&url.URL{
+    u := &url.URL{
         Scheme: r.Scheme,
         Host:   r.Host + ":" + strconv.FormatUint(uint64(r.Port), 10),
         Path:   r.Path,
-    }).String()
+    }
+
+    if r.PortName != "" {
+        u.Host = r.Host + ":" + r.PortName
+    }
+
+    query := r.Options.values()
+    if r.PathType != "" {
+        query.Set("pathType", string(r.PathType))
+    }
+    u.RawQuery = query.Encode()
+
+    if r.Backend != nil {
+        return u.String() + " -> " + r.Backend.String()
+    }
+
+    return u.String()
 }
 
 func (r IngressRule) MarshalText() ([]byte, error) {
@@ -204,6 +386,64 @@ func (r *IngressRule) UnmarshalText(data []byte) error {
     return nil
 }
 
+// ParseIngressBackend parses backend of ingress
+//
+//	static-files:8080
+//	static-files:http-80
+//	:8080 for the service itself
+func ParseIngressBackend(s string) (*IngressBackend, error) {
+    i := strings.LastIndex(s, ":")
+    if i < 0 {
+        return nil, fmt.Errorf("invalid ingress backend %q, should be <service>:<port>", s)
+    }
+
+    b := &IngressBackend{
+        ServiceName: s[:i],
+    }
+
+    if IsPortName(s[i+1:]) {
+        b.PortName = s[i+1:]
+        return b, nil
+    }
+
+    port, err := strconv.ParseUint(s[i+1:], 10, 16)
+    if err != nil || port == 0 {
+        return nil, fmt.Errorf("invalid ingress backend port %q", s[i+1:])
+    }
+    b.Port = uint16(port)
+
+    return b, nil
+}
+
+// openapi:strfmt ingress-backend
+type IngressBackend struct {
+    // the service itself when empty
+    ServiceName string
+    Port        uint16
+    // name of service port, takes precedence over Port
+    PortName string
+}
+
+func (b IngressBackend) String() string {
+    if b.PortName != "" {
+        return b.ServiceName + ":" + b.PortName
+    }
+    return b.ServiceName + ":" + strconv.FormatUint(uint64(b.Port), 10)
+}
+
+func (b IngressBackend) MarshalText() ([]byte, error) {
+    return []byte(b.String()), nil
+}
+
+func (b *IngressBackend) UnmarshalText(data []byte) error {
+    backend, err := ParseIngressBackend(string(data))
+    if err != nil {
+        return err
+    }
+    *b = *backend
+    return nil
+}
+
 // SecretName:host1,host2,host3
 func ParseIngressTLS(s string) (*IngressTLS, error) {
     if s == "" {
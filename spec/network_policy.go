@@ -0,0 +1,73 @@
+package spec
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// NetworkPolicy enables NetworkPolicy of service,
+// which allows ingress on ports of service only
+// and egress to upstreams, DNS and CIDRs only.
+type NetworkPolicy struct {
+	// CIDRs allowed to egress, like 10.0.0.0/8 or 0.0.0.0/0!10.0.0.0/8 with excepts
+	EgressCIDRs []CIDR `json:"egressCIDRs,omitempty" yaml:"egressCIDRs,omitempty"`
+}
+
+// ParseCIDR parses cidr with excepts
+//
+//	10.0.0.0/8
+//	0.0.0.0/0!10.0.0.0/8,172.16.0.0/12
+func ParseCIDR(s string) (*CIDR, error) {
+	parts := strings.Split(s, "!")
+
+	c := &CIDR{CIDR: parts[0]}
+
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("invalid cidr %q", s)
+	}
+
+	if len(parts) == 2 {
+		c.Except = strings.Split(parts[1], ",")
+	}
+
+	for _, cidr := range append([]string{c.CIDR}, c.Except...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("invalid cidr %q", cidr)
+		}
+	}
+
+	return c, nil
+}
+
+// openapi:strfmt cidr
+type CIDR struct {
+	CIDR   string
+	Except []string
+}
+
+func (c CIDR) String() string {
+	if len(c.Except) > 0 {
+		return c.CIDR + "!" + strings.Join(c.Except, ",")
+	}
+	return c.CIDR
+}
+
+func (c CIDR) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *CIDR) UnmarshalText(data []byte) error {
+	cidr, err := ParseCIDR(string(data))
+	if err != nil {
+		return err
+	}
+	*c = *cidr
+	return nil
+}
+
+// UpstreamServiceName returns service name and namespace of upstream,
+// upstream should be <service> or <service>.<namespace>, otherwise ok is false.
+func UpstreamServiceName(upstream string) (name string, namespace string, ok bool) {
+	return Upstream{Host: upstream}.ServiceName()
+}
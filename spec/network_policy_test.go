@@ -0,0 +1,29 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIDR(t *testing.T) {
+	c, err := ParseCIDR("0.0.0.0/0!10.0.0.0/8,172.16.0.0/12")
+	require.NoError(t, err)
+	require.Equal(t, CIDR{CIDR: "0.0.0.0/0", Except: []string{"10.0.0.0/8", "172.16.0.0/12"}}, *c)
+	require.Equal(t, "0.0.0.0/0!10.0.0.0/8,172.16.0.0/12", c.String())
+
+	for _, s := range []string{"", "10.0.0.0", "10.0.0.0/8!x", "a!b!c"} {
+		_, err := ParseCIDR(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestUpstreamServiceName(t *testing.T) {
+	name, namespace, ok := UpstreamServiceName("mysql.db")
+	require.True(t, ok)
+	require.Equal(t, "mysql", name)
+	require.Equal(t, "db", namespace)
+
+	_, _, ok = UpstreamServiceName("api.example.com")
+	require.False(t, ok)
+}
@@ -4,10 +4,45 @@ import (
     "testing"
 
     "github.com/go-courier/helmx/constants"
+    "github.com/go-courier/helmx/kubetypes"
     "github.com/stretchr/testify/require"
     "gopkg.in/yaml.v2"
 )
 
+func TestPortName(t *testing.T) {
+    p, _ := ParsePort("grpc-9000:9090")
+    require.Equal(t, "grpc-9000", p.ServicePortName())
+    require.Equal(t, "grpc-9090", p.ContainerPortName())
+
+    p, _ = ParsePort("!20000:80")
+    require.Equal(t, "np-http-20000", p.ServicePortName())
+    require.Equal(t, "http-80", p.ContainerPortName())
+
+    p, _ = ParsePort("websockets-10080")
+    require.Equal(t, "", p.ContainerPortName())
+
+    p, _ = ParsePort("53/udp")
+    require.Equal(t, "udp-53", p.ServicePortName())
+    require.Equal(t, "udp-53", p.ContainerPortName())
+
+    p, _ = ParsePort("dns-53/udp")
+    require.Equal(t, "dns-53-udp", p.ServicePortName())
+    require.Equal(t, "dns-53-udp", p.ContainerPortName())
+
+    p, _ = ParsePort("!dns-30053:53/udp")
+    require.Equal(t, "np-udp-30053", p.ServicePortName())
+    require.Equal(t, "dns-53-udp", p.ContainerPortName())
+
+    p, _ = ParsePort("diameter-3868/sctp")
+    require.Equal(t, "sctp-3868", p.ServicePortName())
+    require.Equal(t, "sctp-3868", p.ContainerPortName())
+
+    require.True(t, IsPortName("http-80"))
+    require.False(t, IsPortName("80"))
+    require.False(t, IsPortName("http--80"))
+    require.False(t, IsPortName("-http"))
+}
+
 func TestIngressTLS(t *testing.T) {
     t.Run("parse & string", func(t *testing.T) {
         r, _ := ParseIngressTLS("secretName:host1,host2,host3")
@@ -50,6 +85,68 @@ func TestIngressRule(t *testing.T) {
         require.Equal(t, "http://*.helmx:80/helmx", r.String())
     })
 
+    t.Run("path type", func(t *testing.T) {
+        r, err := ParseIngressRule("http://helmx/helmx?pathType=Exact")
+        require.NoError(t, err)
+        require.Equal(t, kubetypes.PathTypeExact, r.PathType)
+        require.Equal(t, "http://helmx:80/helmx?pathType=Exact", r.String())
+
+        _, err = ParseIngressRule("http://helmx/helmx?pathType=Regex")
+        require.Error(t, err)
+    })
+
+    t.Run("options", func(t *testing.T) {
+        r, err := ParseIngressRule("http://helmx/api?rewrite=/&bodySize=8m&middlewares=a,b&annotation=x/y=z")
+        require.NoError(t, err)
+        require.Equal(t, IngressOptions{
+            Rewrite:     "/",
+            BodySize:    "8m",
+            Middlewares: []string{"a", "b"},
+            Annotations: map[string]string{"x/y": "z"},
+        }, r.Options)
+
+        r2, err := ParseIngressRule(r.String())
+        require.NoError(t, err)
+        require.Equal(t, r.Options, r2.Options)
+
+        _, err = ParseIngressRule("http://helmx/api?annotation=x")
+        require.Error(t, err)
+    })
+
+    t.Run("port name", func(t *testing.T) {
+        r, err := ParseIngressRule("http://grpc.helmx:grpc-9000/api")
+        require.NoError(t, err)
+        require.Equal(t, "grpc-9000", r.PortName)
+        require.Equal(t, "grpc.helmx", r.Host)
+        require.Equal(t, "/api", r.Path)
+        require.Equal(t, "http://grpc.helmx:grpc-9000/api", r.String())
+
+        b, err := ParseIngressBackend("static-files:http-80")
+        require.NoError(t, err)
+        require.Equal(t, IngressBackend{ServiceName: "static-files", PortName: "http-80"}, *b)
+
+        _, err = ParseIngressRule("http://helmx:Bad_Name")
+        require.Error(t, err)
+    })
+
+    t.Run("backend", func(t *testing.T) {
+        r, err := ParseIngressRule("http://api.helmx/static -> static-files:8080")
+        require.NoError(t, err)
+        require.Equal(t, &IngressBackend{ServiceName: "static-files", Port: 8080}, r.Backend)
+        require.Equal(t, "http://api.helmx:80/static -> static-files:8080", r.String())
+
+        r, err = ParseIngressRule("http://*/healthz -> :8080")
+        require.NoError(t, err)
+        require.True(t, r.IsWildcardHost())
+        require.Equal(t, "", r.Backend.ServiceName)
+
+        _, err = ParseIngressRule("http://api.helmx -> static-files")
+        require.Error(t, err)
+
+        _, err = ParseIngressRule("http://api.*.helmx")
+        require.Error(t, err)
+    })
+
     t.Run("yaml marshal & unmarshal", func(t *testing.T) {
         data, err := yaml.Marshal(struct {
             IngressRule IngressRule `yaml:"ingress"`
@@ -117,6 +214,26 @@ func TestPort(t *testing.T) {
         require.Equal(t, "!8080", sp.String())
     })
 
+    t.Run("parse & string load balancer", func(t *testing.T) {
+        sp, err := ParsePort("^443:8443")
+        require.NoError(t, err)
+        require.Equal(t, true, sp.IsLoadBalancer)
+        require.Equal(t, false, sp.IsNodePort)
+        require.Equal(t, uint16(443), sp.Port)
+        require.Equal(t, uint16(8443), sp.ContainerPort)
+        require.Equal(t, "^443:8443", sp.String())
+    })
+
+    t.Run("parse & string load balancer with node port", func(t *testing.T) {
+        sp, err := ParsePort("^!30080:80")
+        require.NoError(t, err)
+        require.Equal(t, true, sp.IsLoadBalancer)
+        require.Equal(t, true, sp.IsNodePort)
+        require.Equal(t, uint16(30080), sp.NodePort)
+        require.Equal(t, uint16(80), sp.Port)
+        require.Equal(t, "^!30080:80", sp.String())
+    })
+
     t.Run("parse & string without protocol", func(t *testing.T) {
         sp, _ := ParsePort("80:8080")
         require.Equal(t, uint16(80), sp.Port)
@@ -125,6 +242,13 @@ func TestPort(t *testing.T) {
         require.Equal(t, "80:8080", sp.String())
     })
 
+    t.Run("missing port", func(t *testing.T) {
+        for _, input := range []string{"/udp", "^/udp", "!", "^!"} {
+            _, err := ParsePort(input)
+            require.EqualError(t, err, "missing port", input)
+        }
+    })
+
     t.Run("yaml marshal & unmarshal", func(t *testing.T) {
         data, err := yaml.Marshal(struct {
             Port Port `yaml:"port"`
@@ -148,15 +272,16 @@ func TestPort(t *testing.T) {
     })
 
     t.Run("node port range in 20000-40000", func(t *testing.T) {
-        _, ltErr := ParsePort("!19999:80")
-        _, noLtErr := ParsePort("!20000:80")
-        _, gtErr := ParsePort("!40001:80")
-        _, noGtErr := ParsePort("!40000:80")
-
-        require.Error(t, ltErr)
-        require.NoError(t, noLtErr)
-        require.Error(t, gtErr)
-        require.NoError(t, noGtErr)
+        check := func(s string) error {
+            p, err := ParsePort(s)
+            require.NoError(t, err)
+            return Service{Ports: []Port{*p}}.CheckPorts(DefaultNodePortRange)
+        }
+
+        require.Error(t, check("!19999:80"))
+        require.NoError(t, check("!20000:80"))
+        require.Error(t, check("!40001:80"))
+        require.NoError(t, check("!40000:80"))
     })
 
 }
@@ -1,20 +1,66 @@
 package spec
 
+import (
+	"regexp"
+	"strings"
+)
+
 type Project struct {
-	Name        string  `env:"name" yaml:"name" json:"name"`
-	Feature     string  `env:"feature" yaml:"feature,omitempty" json:"feature,omitempty"`
-	Version     Version `env:"version" yaml:"version" json:"version"`
-	Group       string  `env:"group" yaml:"group,omitempty" json:"group,omitempty"`
-	Description string  `env:"description" yaml:"description,omitempty" json:"description,omitempty"`
+	Name    string  `env:"name" yaml:"name" json:"name"`
+	Feature string  `env:"feature" yaml:"feature,omitempty" json:"feature,omitempty"`
+	Version Version `env:"version" yaml:"version" json:"version"`
+	// source of version, git to derive version from the local git repository, see GitVersion
+	VersionFrom string `env:"versionFrom" yaml:"versionFrom,omitempty" json:"versionFrom,omitempty"`
+	Group       string `env:"group" yaml:"group,omitempty" json:"group,omitempty"`
+	Description string `env:"description" yaml:"description,omitempty" json:"description,omitempty"`
 }
 
+// FullName returns <name>--<feature> as DNS-1123 label, see JoinName
 func (p Project) FullName() string {
-	if p.Feature != "" {
-		return p.Name + "--" + p.Feature
-	}
-	return p.Name
+	return JoinName(p.Name, p.Feature)
 }
 
+// DefaultImageTag returns ~[<group>/]<name>:<version>,
+// + of build metadata is replaced by _, which is not allowed in image tag
 func (p Project) DefaultImageTag() string {
-	return "~" + p.Group + "/" + p.Name + ":" + p.Version.String()
+	repository := p.Name
+	if p.Group != "" {
+		repository = p.Group + "/" + repository
+	}
+	return "~" + repository + ":" + strings.Replace(p.Version.String(), "+", "_", -1)
+}
+
+const ManagedBy = "helmx"
+
+// RecommendedLabels returns the recommended labels app.kubernetes.io/*,
+// version changes every release, so they should never be used in selectors
+func (p Project) RecommendedLabels() map[string]string {
+	labels := map[string]string{
+		"app.kubernetes.io/name":       labelValue(p.Name),
+		"app.kubernetes.io/instance":   labelValue(p.FullName()),
+		"app.kubernetes.io/version":    labelValue(p.Version.String()),
+		"app.kubernetes.io/component":  labelValue(p.Feature),
+		"app.kubernetes.io/part-of":    labelValue(p.Group),
+		"app.kubernetes.io/managed-by": ManagedBy,
+	}
+
+	for k, v := range labels {
+		if v == "" {
+			delete(labels, k)
+		}
+	}
+
+	return labels
+}
+
+var reInvalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// labelValue converts value to valid label value,
+// no longer than 63, with invalid chars replaced by _, and begins and ends with alphanumeric
+func labelValue(v string) string {
+	v = reInvalidLabelValueChars.ReplaceAllString(v, "_")
+	if len(v) > 63 {
+		v = v[:63]
+	}
+	return strings.Trim(v, "_.-")
 }
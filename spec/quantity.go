@@ -0,0 +1,235 @@
+package spec
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type QuantityFormat string
+
+const (
+	// 1e3, 500e-3
+	DecimalExponent QuantityFormat = "DecimalExponent"
+	// 1Ki, 1.5Gi
+	BinarySI QuantityFormat = "BinarySI"
+	// 100m, 1k, 1.5G
+	DecimalSI QuantityFormat = "DecimalSI"
+)
+
+var reQuantity = regexp.MustCompile(`^([+\-]?)([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+\-]?[0-9]+|Ki|Mi|Gi|Ti|Pi|Ei|n|u|m|k|M|G|T|P|E)?$`)
+
+var binarySuffixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+
+// decimal suffixes from 10^-9 to 10^18
+var decimalSuffixes = []string{"n", "u", "m", "", "k", "M", "G", "T", "P", "E"}
+
+var nanoPerUnit = big.NewInt(1e9)
+
+// ParseQuantity parses kubernetes resource quantity
+//
+//	<signedNumber><suffix>
+//	suffix: Ki | Mi | Gi | Ti | Pi | Ei | n | u | m | "" | k | M | G | T | P | E | e<signedNumber> | E<signedNumber>
+//
+// precision beyond nano is rounded up.
+func ParseQuantity(s string) (*Quantity, error) {
+	parts := reQuantity.FindStringSubmatch(s)
+	if parts == nil {
+		return nil, fmt.Errorf("invalid quantity %q", s)
+	}
+
+	number, ok := new(big.Rat).SetString(parts[2])
+	if !ok {
+		return nil, fmt.Errorf("invalid quantity %q", s)
+	}
+
+	q := &Quantity{Format: DecimalSI}
+
+	suffix := parts[3]
+
+	switch {
+	case suffix == "":
+	case suffix[0] == 'e' || (suffix[0] == 'E' && len(suffix) > 1):
+		q.Format = DecimalExponent
+		exp, err := strconv.Atoi(suffix[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q", s)
+		}
+		number.Mul(number, pow10Rat(exp))
+	case strings.HasSuffix(suffix, "i"):
+		q.Format = BinarySI
+		number.Mul(number, new(big.Rat).SetInt(pow1024(indexOf(binarySuffixes, suffix))))
+	default:
+		number.Mul(number, pow10Rat(3*(indexOf(decimalSuffixes, suffix)-3)))
+	}
+
+	number.Mul(number, new(big.Rat).SetInt(nanoPerUnit))
+
+	nano := new(big.Int).Quo(number.Num(), number.Denom())
+	if new(big.Int).Rem(number.Num(), number.Denom()).Sign() != 0 {
+		nano.Add(nano, big.NewInt(1))
+	}
+
+	if parts[1] == "-" {
+		nano.Neg(nano)
+	}
+
+	q.nano = nano
+
+	return q, nil
+}
+
+func MustParseQuantity(s string) Quantity {
+	q, err := ParseQuantity(s)
+	if err != nil {
+		panic(err)
+	}
+	return *q
+}
+
+// openapi:strfmt quantity
+type Quantity struct {
+	// value in nano units
+	nano   *big.Int
+	Format QuantityFormat
+}
+
+func (q Quantity) value() *big.Int {
+	if q.nano == nil {
+		return new(big.Int)
+	}
+	return q.nano
+}
+
+func (q Quantity) IsZero() bool {
+	return q.value().Sign() == 0
+}
+
+func (q Quantity) Sign() int {
+	return q.value().Sign()
+}
+
+// Cmp returns -1, 0 or 1 when q is less than, equal to or greater than o
+func (q Quantity) Cmp(o Quantity) int {
+	return q.value().Cmp(o.value())
+}
+
+func (q Quantity) Add(o Quantity) Quantity {
+	if q.Format == "" {
+		q.Format = o.Format
+	}
+	q.nano = new(big.Int).Add(q.value(), o.value())
+	return q
+}
+
+func (q Quantity) Mul(n int64) Quantity {
+	q.nano = new(big.Int).Mul(q.value(), big.NewInt(n))
+	return q
+}
+
+// Value returns the value rounded up to integer
+func (q Quantity) Value() int64 {
+	return ceilDiv(q.value(), nanoPerUnit).Int64()
+}
+
+// MilliValue returns the value in milli units rounded up to integer
+func (q Quantity) MilliValue() int64 {
+	return ceilDiv(q.value(), big.NewInt(1e6)).Int64()
+}
+
+// String returns the canonical form of the quantity,
+// which is the largest suffix of its format that keeps the number integer.
+func (q Quantity) String() string {
+	v := q.value()
+
+	if v.Sign() == 0 {
+		return "0"
+	}
+
+	sign := ""
+	abs := new(big.Int).Abs(v)
+	if v.Sign() < 0 {
+		sign = "-"
+	}
+
+	if q.Format == BinarySI && isInteger(abs) {
+		units := new(big.Int).Quo(abs, nanoPerUnit)
+		if units.Cmp(big.NewInt(1024)) >= 0 {
+			for i := len(binarySuffixes) - 1; i >= 0; i-- {
+				mantissa, rem := new(big.Int).QuoRem(units, pow1024(i), new(big.Int))
+				if rem.Sign() == 0 {
+					return sign + mantissa.String() + binarySuffixes[i]
+				}
+			}
+		}
+	}
+
+	for i := len(decimalSuffixes) - 1; i >= 0; i-- {
+		mantissa, rem := new(big.Int).QuoRem(abs, pow10Int(3*i), new(big.Int))
+		if rem.Sign() != 0 {
+			continue
+		}
+
+		if q.Format == DecimalExponent {
+			if exp := 3 * (i - 3); exp != 0 {
+				return fmt.Sprintf("%s%se%d", sign, mantissa, exp)
+			}
+			return sign + mantissa.String()
+		}
+
+		return sign + mantissa.String() + decimalSuffixes[i]
+	}
+
+	return sign + abs.String() + "n"
+}
+
+func (q Quantity) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+func (q *Quantity) UnmarshalText(data []byte) error {
+	quantity, err := ParseQuantity(string(data))
+	if err != nil {
+		return err
+	}
+	*q = *quantity
+	return nil
+}
+
+func isInteger(nano *big.Int) bool {
+	return new(big.Int).Rem(nano, nanoPerUnit).Sign() == 0
+}
+
+func ceilDiv(x *big.Int, y *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(x, y, new(big.Int))
+	if rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	return quo
+}
+
+func pow10Int(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func pow10Rat(n int) *big.Rat {
+	if n < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), pow10Int(-n))
+	}
+	return new(big.Rat).SetInt(pow10Int(n))
+}
+
+func pow1024(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(1024), big.NewInt(int64(n)), nil)
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
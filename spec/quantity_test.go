@@ -0,0 +1,93 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestQuantity(t *testing.T) {
+	t.Run("parse & canonical string", func(t *testing.T) {
+		cases := map[string]string{
+			"0":       "0",
+			"1":       "1",
+			"0.5":     "500m",
+			".5":      "500m",
+			"1.5":     "1500m",
+			"100m":    "100m",
+			"1000m":   "1",
+			"1000":    "1k",
+			"1.5k":    "1500",
+			"1Ki":     "1Ki",
+			"1024Ki":  "1Mi",
+			"1.5Gi":   "1536Mi",
+			"0.5Ki":   "512",
+			"1.5Ki":   "1536",
+			"1025Ki":  "1025Ki",
+			"1e3":     "1e3",
+			"1E3":     "1e3",
+			"1000e3":  "1e6",
+			"500e-3":  "500e-3",
+			"1E":      "1E",
+			"2M":      "2M",
+			"-100m":   "-100m",
+			"+1":      "1",
+			"1n":      "1n",
+			"0.1n":    "1n",
+			"12.345u": "12345n",
+		}
+
+		for input, expect := range cases {
+			q, err := ParseQuantity(input)
+			require.NoError(t, err, input)
+			require.Equal(t, expect, q.String(), input)
+		}
+	})
+
+	t.Run("format", func(t *testing.T) {
+		require.Equal(t, BinarySI, MustParseQuantity("1Gi").Format)
+		require.Equal(t, DecimalSI, MustParseQuantity("1G").Format)
+		require.Equal(t, DecimalExponent, MustParseQuantity("1e9").Format)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"", "abc", "1Gb", "1.2.3", "1 Gi", "Mi", "1e"} {
+			_, err := ParseQuantity(input)
+			require.Error(t, err, input)
+		}
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		require.Equal(t, 0, MustParseQuantity("1Gi").Cmp(MustParseQuantity("1024Mi")))
+		require.Equal(t, 0, MustParseQuantity("0.5").Cmp(MustParseQuantity("500m")))
+		require.Equal(t, 1, MustParseQuantity("1G").Cmp(MustParseQuantity("900Mi")))
+		require.Equal(t, -1, MustParseQuantity("1G").Cmp(MustParseQuantity("1Gi")))
+		require.Equal(t, -1, Quantity{}.Cmp(MustParseQuantity("1m")))
+	})
+
+	t.Run("values", func(t *testing.T) {
+		require.Equal(t, int64(1), MustParseQuantity("100m").Value())
+		require.Equal(t, int64(100), MustParseQuantity("100m").MilliValue())
+		require.Equal(t, int64(1073741824), MustParseQuantity("1Gi").Value())
+		require.Equal(t, "6Gi", MustParseQuantity("1Gi").Add(MustParseQuantity("1024Mi")).Mul(3).String())
+	})
+
+	t.Run("yaml marshal & unmarshal", func(t *testing.T) {
+		data, err := yaml.Marshal(struct {
+			Quantity Quantity `yaml:"quantity"`
+		}{
+			Quantity: MustParseQuantity("1.5Gi"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "quantity: 1536Mi\n", string(data))
+
+		v := struct {
+			Quantity Quantity `yaml:"quantity"`
+		}{}
+
+		err = yaml.Unmarshal(data, &v)
+		require.NoError(t, err)
+		require.Equal(t, 0, v.Quantity.Cmp(MustParseQuantity("1.5Gi")))
+	})
+}
@@ -2,85 +2,136 @@ package spec
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
+	"math/big"
+	"strings"
 )
 
 type Resources map[string]*RequestAndLimit
 
-var reRequestAndLimit = regexp.MustCompile(`^([+\-]?[0-9.]+)?(\/([+\-]?[0-9.]+))?([eEinumkKMGTP]*[-+]?[0-9]*)$`)
+// Merge returns resources with entries of srcResources replacing the same resource types,
+// so `cpu: 10m/20m` in container replaces `cpu` of the spec as a whole,
+// and `cpu: 0` could be used to drop it.
+func (resources Resources) Merge(srcResources Resources) Resources {
+	rs := Resources{}
+	for k, v := range resources {
+		rs[k] = v
+	}
+	for k, v := range srcResources {
+		rs[k] = v
+	}
+	return rs
+}
 
+// <request>[/<limit>]
+//
+//	500m/1, 256Mi/1Gi, 0.5, /2Gi
+//
+// the shorthand 10/20m with a shared unit is kept, request without unit takes the unit of limit.
 func ParseRequestAndLimit(s string) (*RequestAndLimit, error) {
-	if s == "" || !reRequestAndLimit.MatchString(s) {
+	if s == "" {
 		return nil, fmt.Errorf("missing request and limit")
 	}
 
-	parts := reRequestAndLimit.FindAllStringSubmatch(s, 1)[0]
+	parts := strings.Split(s, "/")
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("invalid request and limit %s", s)
+	}
 
 	rl := &RequestAndLimit{}
 
-	if parts[1] != "" {
-		i, err := strconv.ParseInt(parts[1], 10, 64)
-		if err == nil {
-			rl.Request = int(i)
+	requestStr := parts[0]
+
+	if len(parts) == 2 {
+		limit, err := ParseQuantity(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit of %s: %s", s, err)
 		}
-	}
+		rl.Limit = *limit
 
-	if parts[3] != "" {
-		i, err := strconv.ParseInt(parts[3], 10, 64)
-		if err == nil {
-			rl.Limit = int(i)
+		if requestStr != "" {
+			if unit := quantityUnit(parts[1]); unit != "" && quantityUnit(requestStr) == "" {
+				requestStr += unit
+			}
 		}
 	}
 
-	if parts[4] != "" {
-		rl.Unit = parts[4]
+	if requestStr != "" {
+		request, err := ParseQuantity(requestStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request of %s: %s", s, err)
+		}
+		rl.Request = *request
 	}
 
 	return rl, nil
 }
 
+func quantityUnit(s string) string {
+	parts := reQuantity.FindStringSubmatch(s)
+	if parts == nil {
+		return ""
+	}
+	return parts[3]
+}
+
 // openapi:strfmt request-and-limit
 type RequestAndLimit struct {
-	Request int
-	Limit   int
-	Unit    string
+	Request Quantity
+	Limit   Quantity
 }
 
 func (s RequestAndLimit) String() string {
-	v := ""
-	if s.Request != 0 {
-		v = strconv.FormatInt(int64(s.Request), 10)
+	request := s.RequestString()
+	limit := s.LimitString()
+
+	if limit == "" {
+		return request
 	}
-	if s.Limit != 0 {
-		v += "/" + strconv.FormatInt(int64(s.Limit), 10)
+
+	if request == "" {
+		return "/" + limit
 	}
-	if s.Unit != "" {
-		v += s.Unit
+
+	requestUnit, limitUnit := quantityUnit(request), quantityUnit(limit)
+
+	switch {
+	case limitUnit == "":
+	case requestUnit == limitUnit:
+		// 10m/20m as 10/20m
+		request = strings.TrimSuffix(request, limitUnit)
+	case requestUnit == "":
+		// request without unit takes the unit of limit when parsing, so 1/1500m should be 1000m/1500m
+		request = quantityStringInUnit(s.Request, limitUnit)
 	}
-	return v
+
+	return request + "/" + limit
 }
 
-func (s RequestAndLimit) RequestString() string {
-	v := ""
-	if s.Request != 0 {
-		v = strconv.FormatInt(int64(s.Request), 10)
+// quantityStringInUnit returns the quantity with the unit when the number is integer,
+// otherwise with the exponent e0, which is also treated as a unit when parsing
+func quantityStringInUnit(q Quantity, unit string) string {
+	perUnit := MustParseQuantity("1" + unit).value()
+
+	mantissa, rem := new(big.Int).QuoRem(q.value(), perUnit, new(big.Int))
+	if rem.Sign() == 0 {
+		return mantissa.String() + unit
 	}
-	if s.Unit != "" {
-		v += s.Unit
+
+	return q.String() + "e0"
+}
+
+func (s RequestAndLimit) RequestString() string {
+	if s.Request.IsZero() {
+		return ""
 	}
-	return v
+	return s.Request.String()
 }
 
 func (s RequestAndLimit) LimitString() string {
-	v := ""
-	if s.Limit != 0 {
-		v = strconv.FormatInt(int64(s.Limit), 10)
-	}
-	if s.Unit != "" {
-		v += s.Unit
+	if s.Limit.IsZero() {
+		return ""
 	}
-	return v
+	return s.Limit.String()
 }
 
 func (s RequestAndLimit) MarshalText() ([]byte, error) {
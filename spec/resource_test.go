@@ -10,8 +10,8 @@ func TestRequestAndLimit(t *testing.T) {
 	t.Run("parse & string", func(t *testing.T) {
 		r, _ := ParseRequestAndLimit("1/500")
 
-		require.Equal(t, 1, r.Request)
-		require.Equal(t, 500, r.Limit)
+		require.Equal(t, "1", r.RequestString())
+		require.Equal(t, "500", r.LimitString())
 
 		require.Equal(t, "1/500", r.String())
 	})
@@ -19,9 +19,8 @@ func TestRequestAndLimit(t *testing.T) {
 	t.Run("parse & string with unit", func(t *testing.T) {
 		r, _ := ParseRequestAndLimit("10/500e6")
 
-		require.Equal(t, 10, r.Request)
-		require.Equal(t, 500, r.Limit)
-		require.Equal(t, "e6", r.Unit)
+		require.Equal(t, "10e6", r.RequestString())
+		require.Equal(t, "500e6", r.LimitString())
 
 		require.Equal(t, "10/500e6", r.String())
 	})
@@ -29,9 +28,96 @@ func TestRequestAndLimit(t *testing.T) {
 	t.Run("parse & string simple", func(t *testing.T) {
 		r, _ := ParseRequestAndLimit("10")
 
-		require.Equal(t, 10, r.Request)
-		require.Equal(t, 0, r.Limit)
+		require.Equal(t, "10", r.RequestString())
+		require.Equal(t, true, r.Limit.IsZero())
 
 		require.Equal(t, "10", r.String())
 	})
+
+	t.Run("parse & string with shared unit", func(t *testing.T) {
+		r, _ := ParseRequestAndLimit("10/20m")
+		require.Equal(t, "10m", r.RequestString())
+		require.Equal(t, "20m", r.LimitString())
+		require.Equal(t, "10/20m", r.String())
+
+		r, _ = ParseRequestAndLimit("0/20Mi")
+		require.Equal(t, "", r.RequestString())
+		require.Equal(t, "20Mi", r.LimitString())
+		require.Equal(t, "/20Mi", r.String())
+	})
+
+	t.Run("parse & string with fraction", func(t *testing.T) {
+		r, _ := ParseRequestAndLimit("0.5/1.5Gi")
+		require.Equal(t, "512Mi", r.RequestString())
+		require.Equal(t, "1536Mi", r.LimitString())
+
+		r, _ = ParseRequestAndLimit("0.5")
+		require.Equal(t, "500m", r.RequestString())
+		require.Equal(t, "500m", r.String())
+	})
+
+	t.Run("parse & string with independent units", func(t *testing.T) {
+		r, _ := ParseRequestAndLimit("256Mi/1Gi")
+		require.Equal(t, "256Mi", r.RequestString())
+		require.Equal(t, "1Gi", r.LimitString())
+		require.Equal(t, "256Mi/1Gi", r.String())
+
+		r, _ = ParseRequestAndLimit("500m/1")
+		require.Equal(t, "500m", r.RequestString())
+		require.Equal(t, "1", r.LimitString())
+		require.Equal(t, "500m/1", r.String())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		cases := map[string]string{
+			"1/500":        "1/500",
+			"10/20m":       "10/20m",
+			"10m/20m":      "10/20m",
+			"1/1.5":        "1000m/1500m",
+			"1/1500m":      "1/1500m",
+			"500m/1":       "500m/1",
+			"256Mi/1Gi":    "256Mi/1Gi",
+			"/2Gi":         "/2Gi",
+			"0.5/1.5Gi":    "512/1536Mi",
+			"1e0/1.5Gi":    "1e0/1536Mi",
+			"10/500e6":     "10/500e6",
+			"1e0/1500e-3":  "1000e-3/1500e-3",
+			"1000001/1.5m": "1000001m/1500u",
+		}
+
+		for input, output := range cases {
+			r, err := ParseRequestAndLimit(input)
+			require.NoError(t, err, input)
+			require.Equal(t, output, r.String(), input)
+
+			r2, err := ParseRequestAndLimit(r.String())
+			require.NoError(t, err, input)
+			require.Equal(t, 0, r.Request.Cmp(r2.Request), "request of %s: %s", input, r.String())
+			require.Equal(t, 0, r.Limit.Cmp(r2.Limit), "limit of %s: %s", input, r.String())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, input := range []string{"", "1/2/3", "x/1", "1/1Gb"} {
+			_, err := ParseRequestAndLimit(input)
+			require.Error(t, err, input)
+		}
+	})
+}
+
+func TestResources(t *testing.T) {
+	t.Run("merge", func(t *testing.T) {
+		spec := Resources{
+			"cpu":    {Request: MustParseQuantity("1"), Limit: MustParseQuantity("2")},
+			"memory": {Request: MustParseQuantity("1Gi"), Limit: MustParseQuantity("4Gi")},
+		}
+
+		rs := spec.Merge(Resources{
+			"memory": {Request: MustParseQuantity("64Mi")},
+		})
+
+		require.Equal(t, "1/2", rs["cpu"].String())
+		require.Equal(t, "64Mi", rs["memory"].String())
+		require.Equal(t, "1/4Gi", spec["memory"].String())
+	})
 }
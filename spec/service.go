@@ -3,29 +3,163 @@ package spec
 import (
     "bytes"
     "errors"
+    "fmt"
     "io"
     "strings"
 
+    "github.com/go-courier/helmx/constants"
     "github.com/go-courier/helmx/kubetypes"
 )
 
 type Service struct {
     Pod                      `yaml:",inline"`
     kubetypes.DeploymentOpts `yaml:",inline"`
+    kubetypes.IngressOpts    `yaml:",inline"`
+    kubetypes.ServiceOpts    `yaml:",inline"`
 
     Ports     []Port        `json:"ports,omitempty" yaml:"ports,omitempty"`
     Ingresses []IngressRule `json:"ingresses,omitempty" yaml:"ingresses,omitempty"`
     TLS       []IngressTLS  `json:"tls,omitempty" yaml:"tls,omitempty"`
+    // options of all ingress rules
+    IngressOptions *IngressOptions `json:"ingress,omitempty" yaml:"ingress,omitempty"`
+    // backend for requests not matched by any ingress rule
+    IngressDefaultBackend *IngressBackend `json:"ingressDefaultBackend,omitempty" yaml:"ingressDefaultBackend,omitempty"`
 
     Headless bool `json:"headless,omitempty" yaml:"headless,omitempty"`
 }
 
+// PortOf returns the port of service by port number or name of service port
+func (s Service) PortOf(port uint16, name string) (Port, bool) {
+    for _, p := range s.Ports {
+        if name != "" {
+            if p.ServicePortName() == name {
+                return p, true
+            }
+            continue
+        }
+        if p.Port == port {
+            return p, true
+        }
+    }
+    return Port{}, false
+}
+
+// ContainerPorts returns ports of container targeted by service ports,
+// deduplicated by container port and protocol, the first service port targeting it takes the name.
+func (s Service) ContainerPorts() []Port {
+    ports := make([]Port, 0, len(s.Ports))
+    indexes := map[string]bool{}
+
+    for _, p := range s.Ports {
+        if p.ContainerPort == 0 {
+            p.ContainerPort = p.Port
+        }
+
+        if key := p.containerPortKey(); !indexes[key] {
+            indexes[key] = true
+            ports = append(ports, p)
+        }
+    }
+
+    return ports
+}
+
+// ContainerPortNameOf returns name of container port targeted by the service port, see ContainerPorts
+func (s Service) ContainerPortNameOf(port Port) string {
+    if port.ContainerPort == 0 {
+        port.ContainerPort = port.Port
+    }
+
+    for _, p := range s.ContainerPorts() {
+        if p.containerPortKey() == port.containerPortKey() {
+            return p.ContainerPortName()
+        }
+    }
+
+    return port.ContainerPortName()
+}
+
+// CheckPorts reports node ports out of range,
+// and duplicate service ports, service port names, container port names and node ports.
+// service ports targeting the same container port are allowed, see ContainerPorts.
+//
+// ports exposed by load balancer should not be mixed with other ports,
+// because all ports of a LoadBalancer service are exposed by the load balancer.
+func (s Service) CheckPorts(nodePortRange PortRange) error {
+    problems := make([]string, 0)
+
+    servicePorts := map[string]bool{}
+    servicePortNames := map[string]bool{}
+    containerPortNames := map[string]bool{}
+    nodePorts := map[uint16]bool{}
+
+    for _, p := range s.Ports {
+        protocol := p.Protocol
+        if protocol == "" {
+            protocol = constants.ProtocolTCP
+        }
+
+        servicePort := fmt.Sprintf("%d/%s", p.Port, protocol)
+        if servicePorts[servicePort] {
+            problems = append(problems, "duplicate service port "+servicePort)
+        }
+        servicePorts[servicePort] = true
+
+        if name := p.ServicePortName(); servicePortNames[name] {
+            problems = append(problems, "duplicate service port name "+name)
+        } else {
+            servicePortNames[name] = true
+        }
+
+        if p.IsNodePort && p.NodePort != 0 {
+            if !nodePortRange.Contains(p.NodePort) {
+                problems = append(problems, fmt.Sprintf("node port %d out of range %s", p.NodePort, nodePortRange))
+            }
+            if nodePorts[p.NodePort] {
+                problems = append(problems, fmt.Sprintf("duplicate node port %d", p.NodePort))
+            }
+            nodePorts[p.NodePort] = true
+        }
+    }
+
+    loadBalancerPorts, otherPorts := make([]string, 0), make([]string, 0)
+    for _, p := range s.Ports {
+        if p.IsLoadBalancer {
+            loadBalancerPorts = append(loadBalancerPorts, p.String())
+        } else {
+            otherPorts = append(otherPorts, p.String())
+        }
+    }
+    if len(loadBalancerPorts) > 0 && len(otherPorts) > 0 {
+        problems = append(problems, fmt.Sprintf("ports %s should be exposed by load balancer as %s", strings.Join(otherPorts, " "), strings.Join(loadBalancerPorts, " ")))
+    }
+
+    for _, p := range s.ContainerPorts() {
+        name := p.ContainerPortName()
+        if name == "" {
+            continue
+        }
+        if containerPortNames[name] {
+            problems = append(problems, "duplicate container port name "+name)
+        }
+        containerPortNames[name] = true
+    }
+
+    if len(problems) > 0 {
+        return fmt.Errorf("invalid ports: %s", strings.Join(problems, ", "))
+    }
+
+    return nil
+}
+
 type Pod struct {
     Initials                []Container `json:"initials,omitempty" yaml:"initials,omitempty"`
     Container               `yaml:",inline"`
     kubetypes.PodOpts       `yaml:",inline"`
     ServiceAccountRoleRules []RoleRule `yaml:"serviceAccountRoleRules,omitempty" json:"serviceAccountRoleRules,omitempty"`
     Hosts                   []Hosts    `yaml:"hosts,omitempty" json:"hosts,omitempty"`
+    // opt-in to wait for upstreams before initials
+    WaitForUpstreams *WaitForUpstreams `yaml:"waitForUpstreams,omitempty" json:"waitForUpstreams,omitempty"`
 }
 
 func ParseRoleRule(r string) (*RoleRule, error) {
@@ -30,3 +30,79 @@ func TestRoleRule(t *testing.T) {
 		require.Equal(t, "secrets#get,update", r.String())
 	})
 }
+
+func TestServiceCheckPorts(t *testing.T) {
+	service := func(ports ...string) Service {
+		s := Service{}
+		for _, port := range ports {
+			p, err := ParsePort(port)
+			require.NoError(t, err)
+			s.Ports = append(s.Ports, *p)
+		}
+		return s
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, service("!30000:80", "!8080", "81:8081").CheckPorts(PortRange{Min: 30000, Max: 32767}))
+		require.NoError(t, service("53/udp", "53/tcp").CheckPorts(DefaultNodePortRange))
+		require.NoError(t, service("80:8080", "8080", "grpc-9000:8080").CheckPorts(DefaultNodePortRange))
+	})
+
+	t.Run("load balancer mixed", func(t *testing.T) {
+		require.NoError(t, service("^443:8443", "^!30080:80").CheckPorts(PortRange{Min: 30000, Max: 32767}))
+		require.EqualError(t,
+			service("^443:8443", "!30080:80", "81").CheckPorts(PortRange{Min: 30000, Max: 32767}),
+			"invalid ports: ports !30080:80 81 should be exposed by load balancer as ^443:8443",
+		)
+	})
+
+	t.Run("node port out of range", func(t *testing.T) {
+		require.EqualError(t, service("!20000:80").CheckPorts(PortRange{Min: 30000, Max: 32767}), "invalid ports: node port 20000 out of range 30000-32767")
+	})
+
+	t.Run("duplicates", func(t *testing.T) {
+		require.EqualError(t,
+			service("!30000:80", "!30000:81", "80:8080").CheckPorts(PortRange{Min: 30000, Max: 32767}),
+			"invalid ports: duplicate service port name np-http-30000, duplicate node port 30000, duplicate service port 80/TCP",
+		)
+		require.EqualError(t,
+			service("53/udp", "dns-53/udp", "53/tcp").CheckPorts(DefaultNodePortRange),
+			"invalid ports: duplicate service port 53/UDP",
+		)
+		require.EqualError(t,
+			service("53/udp", "udp-5353:53/tcp").CheckPorts(DefaultNodePortRange),
+			"invalid ports: duplicate container port name udp-53",
+		)
+	})
+}
+
+func TestServiceContainerPorts(t *testing.T) {
+	s := Service{}
+	for _, port := range []string{"80:8080", "8080", "grpc-9000:8080", "8080/udp", "81"} {
+		p, err := ParsePort(port)
+		require.NoError(t, err)
+		s.Ports = append(s.Ports, *p)
+	}
+
+	names := make([]string, 0)
+	for _, p := range s.ContainerPorts() {
+		names = append(names, p.ContainerPortName())
+	}
+	require.Equal(t, []string{"http-8080", "udp-8080", "http-81"}, names)
+
+	require.Equal(t, "http-8080", s.ContainerPortNameOf(s.Ports[2]))
+	require.Equal(t, "udp-8080", s.ContainerPortNameOf(s.Ports[3]))
+}
+
+func TestPortRange(t *testing.T) {
+	r, err := ParsePortRange("30000-32767")
+	require.NoError(t, err)
+	require.Equal(t, PortRange{Min: 30000, Max: 32767}, *r)
+	require.Equal(t, "30000-32767", r.String())
+
+	_, err = ParsePortRange("32767-30000")
+	require.Error(t, err)
+
+	_, err = ParsePortRange("30000")
+	require.Error(t, err)
+}
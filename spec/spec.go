@@ -2,6 +2,8 @@ package spec
 
 type Spec struct {
 	Project *Project `json:"project,omitempty" yaml:"project,omitempty"`
+	// namespace of generated objects, see ParseNamespace
+	Namespace *Namespace `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 
 	Service *Service       `json:"service,omitempty" yaml:"service,omitempty"`
 	Jobs    map[string]Job `json:"jobs,omitempty" yaml:"jobs,omitempty"`
@@ -10,8 +12,20 @@ type Spec struct {
 	Envs        Envs         `json:"envs,omitempty" yaml:"envs,omitempty"`
 	Tolerations []Toleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
 	Resources   Resources    `json:"resources,omitempty" yaml:"resources,omitempty"`
-	// just host or service name list
+	// host or service name list, with optional scheme and port, see ParseUpstream
 	Upstreams []string `json:"upstreams,omitempty" yaml:"upstreams,omitempty"`
-	// labels
+	// derive env vars from upstreams, explicit envs take precedence
+	UpstreamEnvs *UpstreamEnvs `json:"upstreamEnvs,omitempty" yaml:"upstreamEnvs,omitempty"`
+	// NetworkPolicy of service
+	NetworkPolicy *NetworkPolicy `json:"networkPolicy,omitempty" yaml:"networkPolicy,omitempty"`
+	// labels of all generated objects and pod templates
 	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// annotations of all generated objects
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	// annotations of pod templates
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty" yaml:"podAnnotations,omitempty"`
+	// labels and annotations overrides by kind of object, see ObjectMetaOf
+	Metadata map[string]ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// cluster to render for
+	Target *Target `json:"target,omitempty" yaml:"target,omitempty"`
 }
@@ -0,0 +1,101 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	IngressAPIVersionNetworkingV1      = "networking.k8s.io/v1"
+	IngressAPIVersionNetworkingV1beta1 = "networking.k8s.io/v1beta1"
+	IngressAPIVersionExtensionsV1beta1 = "extensions/v1beta1"
+)
+
+// Target describes the cluster which the spec is rendered for
+type Target struct {
+	// apiVersion of Ingress, networking.k8s.io/v1 as default
+	IngressVersion string `json:"ingressVersion,omitempty" yaml:"ingressVersion,omitempty"`
+	// range of node ports, 20000-40000 as default
+	NodePortRange *PortRange `json:"nodePortRange,omitempty" yaml:"nodePortRange,omitempty"`
+	// override name of namespace of spec, see ParseNamespace, options like create follow namespace of spec
+	Namespace *Namespace `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	// parent Gateway of HTTPRoute
+	Gateway *GatewayRef `json:"gateway,omitempty" yaml:"gateway,omitempty"`
+}
+
+func (t *Target) IngressAPIVersion() string {
+	if t == nil || t.IngressVersion == "" {
+		return IngressAPIVersionNetworkingV1
+	}
+	return t.IngressVersion
+}
+
+// IsLegacyIngress returns true when the backend of Ingress should be serviceName and servicePort
+func (t *Target) IsLegacyIngress() bool {
+	switch t.IngressAPIVersion() {
+	case IngressAPIVersionNetworkingV1beta1, IngressAPIVersionExtensionsV1beta1:
+		return true
+	}
+	return false
+}
+
+var DefaultNodePortRange = PortRange{Min: 20000, Max: 40000}
+
+func (t *Target) ResolveNodePortRange() PortRange {
+	if t == nil || t.NodePortRange == nil {
+		return DefaultNodePortRange
+	}
+	return *t.NodePortRange
+}
+
+// ParsePortRange parses port range like 30000-32767
+func ParsePortRange(s string) (*PortRange, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid port range %q, should be <min>-<max>", s)
+	}
+
+	min, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port range %q: %s", s, err)
+	}
+
+	max, err := strconv.ParseUint(parts[1], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port range %q: %s", s, err)
+	}
+
+	if min == 0 || min > max {
+		return nil, fmt.Errorf("invalid port range %q", s)
+	}
+
+	return &PortRange{Min: uint16(min), Max: uint16(max)}, nil
+}
+
+// openapi:strfmt port-range
+type PortRange struct {
+	Min uint16
+	Max uint16
+}
+
+func (r PortRange) Contains(port uint16) bool {
+	return port >= r.Min && port <= r.Max
+}
+
+func (r PortRange) String() string {
+	return strconv.FormatUint(uint64(r.Min), 10) + "-" + strconv.FormatUint(uint64(r.Max), 10)
+}
+
+func (r PortRange) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *PortRange) UnmarshalText(data []byte) error {
+	portRange, err := ParsePortRange(string(data))
+	if err != nil {
+		return err
+	}
+	*r = *portRange
+	return nil
+}
@@ -0,0 +1,288 @@
+package spec
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var reDNSLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+var reDNSName = regexp.MustCompile(`^(?i)[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// ParseUpstream parses upstream
+//
+//	redis
+//	mysql.db
+//	mysql:3306
+//	tcp://mysql:3306
+//	http://api:8080/healthz
+//	http://api:8080/healthz?ready=1
+//	db=postgres.internal.example.com:5432
+//	db=10.0.0.10:5432
+//
+// upstream with name is external, which is exposed as service of the name in cluster.
+func ParseUpstream(s string) (*Upstream, error) {
+	if s == "" {
+		return nil, fmt.Errorf("missing upstream")
+	}
+
+	name := ""
+
+	if i := strings.Index(s, "="); i > 0 && reDNSLabel.MatchString(s[:i]) {
+		name = s[:i]
+		s = s[i+1:]
+	}
+
+	if !strings.Contains(s, "://") {
+		s = "tcp://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream %q: %s", s, err)
+	}
+
+	upstream := &Upstream{
+		Name:   name,
+		Scheme: u.Scheme,
+		Host:   u.Hostname(),
+		Path:   u.Path,
+		Query:  u.RawQuery,
+	}
+
+	switch upstream.Scheme {
+	case "tcp", "http", "https":
+	default:
+		return nil, fmt.Errorf("invalid upstream %q, unsupported scheme %q", s, upstream.Scheme)
+	}
+
+	if upstream.Host == "" {
+		return nil, fmt.Errorf("invalid upstream %q, missing host", s)
+	}
+
+	if !upstream.IsIP() && (len(upstream.Host) > 253 || !reDNSName.MatchString(upstream.Host)) {
+		return nil, fmt.Errorf("invalid upstream %q, host should be dns name or ip", s)
+	}
+
+	if p := u.Port(); p != "" {
+		port, err := strconv.ParseUint(p, 10, 16)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("invalid upstream %q, invalid port %q", s, p)
+		}
+		upstream.Port = uint16(port)
+	}
+
+	if upstream.Name != "" && upstream.IsIP() && upstream.Port == 0 {
+		return nil, fmt.Errorf("invalid upstream %q, missing port of external ip", s)
+	}
+
+	return upstream, nil
+}
+
+// openapi:strfmt upstream
+type Upstream struct {
+	// service name in cluster of external upstream
+	Name string
+	// tcp, http or https, tcp as default
+	Scheme string
+	// service name, <service>.<namespace> or external host
+	Host string
+	// port to wait for, upstream without port is waited by dns resolution of host
+	Port uint16
+	// path of http check
+	Path string
+	// raw query of http check
+	Query string
+}
+
+// IsExternal returns true when upstream should be exposed as service in cluster
+func (u Upstream) IsExternal() bool {
+	return u.Name != ""
+}
+
+func (u Upstream) IsIP() bool {
+	return net.ParseIP(u.Host) != nil
+}
+
+func (u Upstream) Address() string {
+	if u.Port == 0 {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Host, strconv.FormatUint(uint64(u.Port), 10))
+}
+
+func (u Upstream) String() string {
+	prefix := ""
+	if u.Name != "" {
+		prefix = u.Name + "="
+	}
+
+	if (u.Scheme == "" || u.Scheme == "tcp") && u.Port == 0 {
+		return prefix + u.Host
+	}
+
+	scheme := u.Scheme
+	if scheme == "" {
+		scheme = "tcp"
+	}
+
+	host := u.Address()
+	if u.Port == 0 && strings.Contains(u.Host, ":") {
+		host = "[" + u.Host + "]"
+	}
+
+	query := ""
+	if u.Query != "" {
+		query = "?" + u.Query
+	}
+
+	return prefix + scheme + "://" + host + u.Path + query
+}
+
+func (u Upstream) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *Upstream) UnmarshalText(data []byte) error {
+	upstream, err := ParseUpstream(string(data))
+	if err != nil {
+		return err
+	}
+	*u = *upstream
+	return nil
+}
+
+// ServiceName returns service name and namespace of upstream,
+// host of upstream should be <service> or <service>.<namespace>, otherwise ok is false.
+func (u Upstream) ServiceName() (name string, namespace string, ok bool) {
+	if u.IsExternal() {
+		return "", "", false
+	}
+
+	parts := strings.Split(u.Host, ".")
+
+	switch len(parts) {
+	case 1:
+		return parts[0], "", parts[0] != ""
+	case 2:
+		return parts[0], parts[1], parts[0] != "" && parts[1] != ""
+	}
+
+	return "", "", false
+}
+
+// ResolveUpstreams parses upstreams of spec
+func (s Spec) ResolveUpstreams() ([]Upstream, error) {
+	upstreams := make([]Upstream, 0, len(s.Upstreams))
+
+	for _, v := range s.Upstreams {
+		u, err := ParseUpstream(v)
+		if err != nil {
+			return nil, err
+		}
+		upstreams = append(upstreams, *u)
+	}
+
+	return upstreams, nil
+}
+
+// WaitForUpstreams generates init containers which wait for upstreams before initials,
+// by port when upstream with port, otherwise by dns resolution of host
+type WaitForUpstreams struct {
+	// timeout of each upstream, 60 as default
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty" yaml:"timeoutSeconds,omitempty"`
+	// image with sh, timeout, nc, wget and nslookup, busybox as default
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+}
+
+var reNonEnvNameChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// EnvName returns name of upstream for env vars in upper snake case,
+// which is name of external upstream, service name or host.
+func (u Upstream) EnvName() string {
+	name := u.Name
+	if name == "" {
+		name = u.Host
+		if serviceName, _, ok := u.ServiceName(); ok {
+			name = serviceName
+		}
+	}
+	return strings.Trim(reNonEnvNameChars.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+}
+
+// InClusterHost returns host to connect to upstream in cluster,
+// which is service name of external upstream.
+func (u Upstream) InClusterHost() string {
+	if u.IsExternal() {
+		return u.Name
+	}
+	return u.Host
+}
+
+// UpstreamEnvs derives env vars of host, port and url from upstreams
+type UpstreamEnvs struct {
+	// naming of env var, {NAME} for name of upstream, {KEY} for HOST, PORT or URL, {NAME}_{KEY} as default
+	Naming string `json:"naming,omitempty" yaml:"naming,omitempty"`
+}
+
+func (e UpstreamEnvs) envKey(name string, key string) string {
+	naming := e.Naming
+	if naming == "" {
+		naming = "{NAME}_{KEY}"
+	}
+	return strings.NewReplacer("{NAME}", name, "{KEY}", key).Replace(naming)
+}
+
+// ResolveUpstreamEnvs returns env vars derived from upstreams when UpstreamEnvs enabled.
+//
+//	REDIS_HOST=redis
+//	MYSQL_HOST=mysql, MYSQL_PORT=3306, MYSQL_URL=tcp://mysql:3306
+//
+// port and url are only for upstreams with port, upstreams with same env name are not allowed.
+func (s Spec) ResolveUpstreamEnvs() (Envs, error) {
+	if s.UpstreamEnvs == nil {
+		return nil, nil
+	}
+
+	upstreams, err := s.ResolveUpstreams()
+	if err != nil {
+		return nil, err
+	}
+
+	envs := Envs{}
+	sources := map[string]Upstream{}
+
+	for _, u := range upstreams {
+		name := u.EnvName()
+
+		set := func(key string, value string) error {
+			k := s.UpstreamEnvs.envKey(name, key)
+			if prev, ok := sources[k]; ok {
+				return fmt.Errorf("duplicate upstream env %s of %s and %s", k, prev.String(), u.String())
+			}
+			sources[k] = u
+			envs[k] = value
+			return nil
+		}
+
+		if err := set("HOST", u.InClusterHost()); err != nil {
+			return nil, err
+		}
+
+		if u.Port != 0 {
+			inCluster := Upstream{Scheme: u.Scheme, Host: u.InClusterHost(), Port: u.Port, Path: u.Path, Query: u.Query}
+
+			if err := set("PORT", strconv.FormatUint(uint64(u.Port), 10)); err != nil {
+				return nil, err
+			}
+			if err := set("URL", inCluster.String()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return envs, nil
+}
@@ -0,0 +1,93 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstream(t *testing.T) {
+	cases := map[string]Upstream{
+		"redis":                         {Scheme: "tcp", Host: "redis"},
+		"mysql:3306":                    {Scheme: "tcp", Host: "mysql", Port: 3306},
+		"tcp://mysql.db:3306":           {Scheme: "tcp", Host: "mysql.db", Port: 3306},
+		"http://api:8080/healthz":       {Scheme: "http", Host: "api", Port: 8080, Path: "/healthz"},
+		"http://api:8080/healthz?a=1&b": {Scheme: "http", Host: "api", Port: 8080, Path: "/healthz", Query: "a=1&b"},
+		"db=postgres.example.com:5432":  {Name: "db", Scheme: "tcp", Host: "postgres.example.com", Port: 5432},
+		"cache=[fd00::10]:6379":         {Name: "cache", Scheme: "tcp", Host: "fd00::10", Port: 6379},
+	}
+
+	for s, expect := range cases {
+		u, err := ParseUpstream(s)
+		require.NoError(t, err, s)
+		require.Equal(t, expect, *u, s)
+
+		u2, err := ParseUpstream(u.String())
+		require.NoError(t, err, s)
+		require.Equal(t, *u, *u2, s)
+	}
+
+	for _, s := range []string{"", "udp://dns:53", "mysql:0", "tcp://:3306", "db=10.0.0.10", "a;reboot", "$(id)", "http://a;reboot:80/", "api_v1:8080"} {
+		_, err := ParseUpstream(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestUpstream_ServiceName(t *testing.T) {
+	name, namespace, ok := Upstream{Host: "mysql.db"}.ServiceName()
+	require.True(t, ok)
+	require.Equal(t, "mysql", name)
+	require.Equal(t, "db", namespace)
+
+	_, _, ok = Upstream{Host: "api.example.com"}.ServiceName()
+	require.False(t, ok)
+}
+
+func TestResolveUpstreamEnvs(t *testing.T) {
+	s := Spec{
+		Upstreams: []string{"redis", "tcp://mysql.db:3306", "db=postgres.example.com:5432"},
+	}
+
+	envs, err := s.ResolveUpstreamEnvs()
+	require.NoError(t, err)
+	require.Nil(t, envs)
+
+	s.UpstreamEnvs = &UpstreamEnvs{}
+
+	envs, err = s.ResolveUpstreamEnvs()
+	require.NoError(t, err)
+	require.Equal(t, Envs{
+		"REDIS_HOST": "redis",
+		"MYSQL_HOST": "mysql.db",
+		"MYSQL_PORT": "3306",
+		"MYSQL_URL":  "tcp://mysql.db:3306",
+		"DB_HOST":    "db",
+		"DB_PORT":    "5432",
+		"DB_URL":     "tcp://db:5432",
+	}, envs)
+
+	s.UpstreamEnvs.Naming = "UPSTREAM_{NAME}_{KEY}"
+	s.Upstreams = []string{"redis"}
+
+	envs, err = s.ResolveUpstreamEnvs()
+	require.NoError(t, err)
+	require.Equal(t, Envs{"UPSTREAM_REDIS_HOST": "redis"}, envs)
+
+	s.UpstreamEnvs.Naming = ""
+	s.Upstreams = []string{"http://api:8080/healthz?ready=1"}
+
+	envs, err = s.ResolveUpstreamEnvs()
+	require.NoError(t, err)
+	require.Equal(t, "http://api:8080/healthz?ready=1", envs["API_URL"])
+
+	s.Upstreams = []string{"redis", "redis.cache"}
+
+	_, err = s.ResolveUpstreamEnvs()
+	require.Error(t, err)
+
+	s.UpstreamEnvs.Naming = "{KEY}"
+	s.Upstreams = []string{"redis", "mysql"}
+
+	_, err = s.ResolveUpstreamEnvs()
+	require.Error(t, err)
+}
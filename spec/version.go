@@ -2,44 +2,55 @@ package spec
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
-var versionRegexp = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
+// [<prefix>-]<major>.<minor>.<patch>[-<pre-release>][+<build>], prefix should begin with letter
+var versionRegexp = regexp.MustCompile(`^(?:([A-Za-z][0-9A-Za-z._-]*?)-)?v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
 
+// ParseVersion parses SemVer 2.0 version with optional prefix, like
+//
+//	1.2.3
+//	1.2.3-rc.1+build.5
+//	feat-1.2.3-rc.1
 func ParseVersion(s string) (*Version, error) {
-	matched := versionRegexp.FindAllStringSubmatch(s, -1)
-
-	if len(matched) == 0 || len(matched[0]) != 4 {
+	matched := versionRegexp.FindStringSubmatch(s)
+	if matched == nil {
 		return nil, errors.New(s + " is not an available version")
 	}
 
-	parts := matched[0]
+	v := &Version{
+		Prefix: matched[1],
+		Suffix: matched[5],
+		Build:  matched[6],
+	}
 
-	major, _ := strconv.ParseInt(parts[1], 10, 10)
-	minor, _ := strconv.ParseInt(parts[2], 10, 10)
-	patch, _ := strconv.ParseInt(parts[3], 10, 10)
+	for i, n := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		num, err := parseVersionNumber(matched[2+i])
+		if err != nil {
+			return nil, fmt.Errorf("%s is not an available version: %s", s, err)
+		}
+		*n = num
+	}
 
-	v := &Version{
-		Major: int(major),
-		Minor: int(minor),
-		Patch: int(patch),
-	}
-
-	if s != parts[0] {
-		ps := strings.Split(s, "-")
-
-		switch len(ps) {
-		case 3:
-			v.Suffix = ps[2]
-			v.Prefix = ps[0]
-		case 2:
-			if parts[0] == ps[0] {
-				v.Suffix = ps[1]
-			} else {
-				v.Prefix = ps[0]
+	for _, id := range v.PreRelease() {
+		if id == "" {
+			return nil, fmt.Errorf("%s is not an available version: empty pre-release identifier", s)
+		}
+		if isNumericIdentifier(id) {
+			if _, err := parseVersionNumber(id); err != nil {
+				return nil, fmt.Errorf("%s is not an available version: %s", s, err)
+			}
+		}
+	}
+
+	if v.Build != "" {
+		for _, id := range strings.Split(v.Build, ".") {
+			if id == "" {
+				return nil, fmt.Errorf("%s is not an available version: empty build identifier", s)
 			}
 		}
 	}
@@ -47,13 +58,110 @@ func ParseVersion(s string) (*Version, error) {
 	return v, nil
 }
 
+func parseVersionNumber(s string) (int, error) {
+	if len(s) > 1 && s[0] == '0' {
+		return 0, fmt.Errorf("numeric identifier %s should not have leading zeros", s)
+	}
+	n, err := strconv.ParseUint(s, 10, 31)
+	if err != nil {
+		return 0, fmt.Errorf("invalid numeric identifier %s", s)
+	}
+	return int(n), nil
+}
+
+func isNumericIdentifier(id string) bool {
+	for _, c := range id {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return id != ""
+}
+
 // openapi:strfmt version
 type Version struct {
+	// pre-release, dot separated identifiers
 	Suffix string
 	Prefix string
 	Major  int
 	Minor  int
 	Patch  int
+	// build metadata, ignored in precedence
+	Build string
+}
+
+// PreRelease returns identifiers of pre-release
+func (v Version) PreRelease() []string {
+	if v.Suffix == "" {
+		return nil
+	}
+	return strings.Split(v.Suffix, ".")
+}
+
+// Compare returns -1, 0 or 1 by SemVer precedence, prefix and build metadata are ignored
+func (v Version) Compare(w Version) int {
+	if c := compareInt(v.Major, w.Major); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Minor, w.Minor); c != 0 {
+		return c
+	}
+	if c := compareInt(v.Patch, w.Patch); c != 0 {
+		return c
+	}
+
+	// version without pre-release has higher precedence
+	switch {
+	case v.Suffix == "" && w.Suffix == "":
+		return 0
+	case v.Suffix == "":
+		return 1
+	case w.Suffix == "":
+		return -1
+	}
+
+	ids, otherIDs := v.PreRelease(), w.PreRelease()
+
+	for i := 0; i < len(ids) && i < len(otherIDs); i++ {
+		if c := comparePreReleaseIdentifier(ids[i], otherIDs[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(len(ids), len(otherIDs))
+}
+
+func (v Version) LessThan(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+// numeric identifiers are compared numerically, and have lower precedence than alphanumeric ones
+func comparePreReleaseIdentifier(a string, b string) int {
+	aIsNum, bIsNum := isNumericIdentifier(a), isNumericIdentifier(b)
+
+	switch {
+	case aIsNum && bIsNum:
+		if c := compareInt(len(a), len(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aIsNum:
+		return -1
+	case bIsNum:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareInt(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
 }
 
 func (v Version) String() string {
@@ -73,6 +181,10 @@ func (v Version) String() string {
 		version = version + "-" + v.Suffix
 	}
 
+	if v.Build != "" {
+		version = version + "+" + v.Build
+	}
+
 	return strings.ToLower(version)
 }
 
@@ -0,0 +1,198 @@
+package spec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParseVersionConstraint parses constraint of versions,
+// comparators separated by space should be all matched, and any of ranges separated by || matched.
+//
+//	>=1.2 <2
+//	~1.2.3
+//	^1.2.3 || >=2.1.0-rc.1
+//
+// operators are =, !=, >, >=, <, <=, ~ and ^, = as default.
+// partial version like 1.2 is 1.2.0, except it means 1.2.x with = and != .
+// ~1.2.3 is >=1.2.3 <1.3.0, ~1 is >=1.0.0 <2.0.0,
+// ^1.2.3 is >=1.2.3 <2.0.0, ^0.2.3 is >=0.2.3 <0.3.0 and ^0.0.3 is >=0.0.3 <0.0.4.
+func ParseVersionConstraint(s string) (*VersionConstraint, error) {
+	c := &VersionConstraint{}
+
+	for _, r := range strings.Split(s, "||") {
+		fields := strings.Fields(r)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q, empty range", s)
+		}
+
+		comparators := make([]versionComparator, 0)
+
+		for _, f := range fields {
+			cs, err := parseVersionComparator(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %s", s, err)
+			}
+			comparators = append(comparators, cs...)
+		}
+
+		c.ranges = append(c.ranges, comparators)
+	}
+
+	c.raw = normalizeVersionConstraint(s)
+
+	return c, nil
+}
+
+// openapi:strfmt version-constraint
+type VersionConstraint struct {
+	raw    string
+	ranges [][]versionComparator
+}
+
+// Check returns true when the version matches the constraint
+func (c VersionConstraint) Check(v Version) bool {
+	for _, comparators := range c.ranges {
+		if matchAllVersionComparators(comparators, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c VersionConstraint) String() string {
+	return c.raw
+}
+
+func (c VersionConstraint) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *VersionConstraint) UnmarshalText(data []byte) error {
+	constraint, err := ParseVersionConstraint(string(data))
+	if err != nil {
+		return err
+	}
+	*c = *constraint
+	return nil
+}
+
+func normalizeVersionConstraint(s string) string {
+	ranges := strings.Split(s, "||")
+	for i := range ranges {
+		ranges[i] = strings.Join(strings.Fields(ranges[i]), " ")
+	}
+	return strings.Join(ranges, " || ")
+}
+
+func matchAllVersionComparators(comparators []versionComparator, v Version) bool {
+	for _, c := range comparators {
+		if !c.match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+type versionComparator struct {
+	op      string
+	version Version
+	// for != with partial version, matches when out of [version, upper)
+	upper *Version
+}
+
+func (c versionComparator) match(v Version) bool {
+	r := v.Compare(c.version)
+
+	switch c.op {
+	case "=":
+		return r == 0
+	case "!=":
+		if c.upper != nil {
+			return r < 0 || !v.LessThan(*c.upper)
+		}
+		return r != 0
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	case "<":
+		return r < 0
+	case "<=":
+		return r <= 0
+	}
+	return false
+}
+
+var reVersionComparator = regexp.MustCompile(`^(=|!=|>=|<=|>|<|~|\^)?v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+func parseVersionComparator(s string) ([]versionComparator, error) {
+	matched := reVersionComparator.FindStringSubmatch(s)
+	if matched == nil {
+		return nil, fmt.Errorf("invalid comparator %q", s)
+	}
+
+	op := matched[1]
+	if op == "" {
+		op = "="
+	}
+
+	v := Version{Suffix: matched[5]}
+
+	// count of specified numbers of major, minor and patch
+	n := 0
+	for i, num := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if matched[2+i] == "" {
+			break
+		}
+		value, err := parseVersionNumber(matched[2+i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid comparator %q: %s", s, err)
+		}
+		*num = value
+		n++
+	}
+
+	if n < 3 && v.Suffix != "" {
+		return nil, fmt.Errorf("invalid comparator %q, pre-release should be with full version", s)
+	}
+
+	// upper bound of partial version, 1 -> 2.0.0, 1.2 -> 1.3.0
+	partialUpper := func() Version {
+		upper := v.IncrMinor()
+		if n == 1 {
+			upper = v.IncrMajor()
+		}
+		upper.Suffix = ""
+		return upper
+	}
+
+	switch op {
+	case "=":
+		if n < 3 {
+			upper := partialUpper()
+			return []versionComparator{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+		}
+	case "!=":
+		if n < 3 {
+			upper := partialUpper()
+			return []versionComparator{{op: "!=", version: v, upper: &upper}}, nil
+		}
+	case "~":
+		return []versionComparator{{op: ">=", version: v}, {op: "<", version: partialUpper()}}, nil
+	case "^":
+		// the left-most non-zero number should not be changed
+		upper := v.IncrMajor()
+		switch {
+		case n == 1 || v.Major != 0:
+		case v.Minor == 0 && n == 3:
+			upper = v.IncrPatch()
+		default:
+			upper = v.IncrMinor()
+		}
+		upper.Suffix = ""
+		return []versionComparator{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+	}
+
+	return []versionComparator{{op: op, version: v}}, nil
+}
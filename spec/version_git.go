@@ -0,0 +1,146 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionFromGit is the source of version from the local git repository, see Project.VersionFrom
+const VersionFromGit = "git"
+
+// git describe of tag, <tag>-<commits since tag>-g<short sha>[-dirty]
+var reGitDescribe = regexp.MustCompile(`^(.+)-(\d+)-g([0-9a-f]+)(-dirty)?$`)
+
+// GitVersion derives version from the git repository of dir as git describe,
+// with the latest semver tag, commits since the tag, short sha and dirty state, like
+//
+//	1.4.0                  on tag 1.4.0
+//	1.4.0-3-gabc123        3 commits since tag 1.4.0
+//	1.4.0-3-gabc123-dirty  with uncommitted changes
+//	0.0.0-12-gabc123       without any semver tag
+//
+// tags which are not semver, like release-2020.01.02, are skipped.
+// only the local repository is read, no network access.
+func GitVersion(dir string) (*Version, error) {
+	sha, err := git(dir, "rev-parse", "--short=7", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := latestSemVerTag(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := "HEAD"
+	if tag != "" {
+		revisions = "refs/tags/" + tag + "..HEAD"
+	} else {
+		tag = "0.0.0"
+	}
+
+	commits, err := git(dir, "rev-list", "--count", revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := git(dir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, err
+	}
+
+	describe := tag + "-" + commits + "-g" + sha
+	if status != "" {
+		describe += "-dirty"
+	}
+
+	return ParseGitDescribe(describe)
+}
+
+// latestSemVerTag returns the latest tag reachable from HEAD which could be parsed as version,
+// empty when there is no such tag.
+func latestSemVerTag(dir string) (string, error) {
+	decorations, err := git(dir, "log", "--topo-order", "--simplify-by-decoration", "--decorate-refs=refs/tags/", "--format=%D", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(decorations, "\n") {
+		for _, ref := range strings.Split(line, ", ") {
+			if !strings.HasPrefix(ref, "tag: ") {
+				continue
+			}
+			tag := strings.TrimPrefix(ref, "tag: ")
+			if _, err := ParseVersion(tag); err == nil {
+				return tag, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// ParseGitDescribe parses output of git describe --tags --long --dirty as version
+func ParseGitDescribe(describe string) (*Version, error) {
+	matched := reGitDescribe.FindStringSubmatch(describe)
+	if matched == nil {
+		return nil, fmt.Errorf("invalid git describe %q, should be <tag>-<commits>-g<sha>[-dirty]", describe)
+	}
+
+	v, err := ParseVersion(matched[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid git describe %q: %s", describe, err)
+	}
+
+	commits, _ := strconv.Atoi(matched[2])
+	dirty := matched[4] != ""
+
+	if commits == 0 && !dirty {
+		return v, nil
+	}
+
+	suffix := matched[2] + "-g" + matched[3] + matched[4]
+
+	if v.Suffix != "" {
+		v.Suffix = v.Suffix + "." + suffix
+	} else {
+		v.Suffix = suffix
+	}
+
+	return v, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ResolveVersion sets version from VersionFrom, dir is the working dir of git repository
+func (p *Project) ResolveVersion(dir string) error {
+	switch p.VersionFrom {
+	case "":
+		return nil
+	case VersionFromGit:
+		v, err := GitVersion(dir)
+		if err != nil {
+			return fmt.Errorf("version from git: %w", err)
+		}
+		p.Version = *v
+		return nil
+	}
+	return fmt.Errorf("unknown version source %q", p.VersionFrom)
+}
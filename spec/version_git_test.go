@@ -0,0 +1,102 @@
+package spec
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitDescribe(t *testing.T) {
+	cases := map[string]string{
+		"1.4.0-0-gabc1234":             "1.4.0",
+		"v1.4.0-3-gabc1234":            "1.4.0-3-gabc1234",
+		"1.4.0-0-gabc1234-dirty":       "1.4.0-0-gabc1234-dirty",
+		"feat-1.4.0-rc.1-3-gabc1234":   "feat-1.4.0-rc.1.3-gabc1234",
+		"1.4.0+build.1-12-gabc1234":    "1.4.0-12-gabc1234+build.1",
+		"1.4.0-rc-1-3-gabc1234-dirty":  "1.4.0-rc-1.3-gabc1234-dirty",
+		"0.0.0-12-gabc1234":            "0.0.0-12-gabc1234",
+		"release-1.4.0-1-gabc1234abcd": "release-1.4.0-1-gabc1234abcd",
+	}
+
+	for describe, version := range cases {
+		v, err := ParseGitDescribe(describe)
+		require.NoError(t, err, describe)
+		require.Equal(t, version, v.String(), describe)
+	}
+
+	for _, describe := range []string{"abc1234", "1.4-3-gabc1234", "1.4.0-x-gabc1234"} {
+		_, err := ParseGitDescribe(describe)
+		require.Error(t, err, describe)
+	}
+}
+
+func TestGitVersion(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir, err := ioutil.TempDir("", "helmx-git")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) {
+		_, err := git(dir, append([]string{"-c", "user.name=helmx", "-c", "user.email=helmx@helmx.io", "-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, args...)...)
+		require.NoError(t, err)
+	}
+
+	commit := func(content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte(content), 0644))
+		run("add", "file")
+		run("commit", "-q", "-m", content)
+	}
+
+	run("init", "-q")
+
+	_, err = GitVersion(dir)
+	require.Error(t, err, "without commits")
+
+	commit("1")
+	commit("2")
+
+	v, err := GitVersion(dir)
+	require.NoError(t, err)
+	require.Regexp(t, `^0\.0\.0-2-g[0-9a-f]{7,}$`, v.String())
+
+	run("tag", "1.4.0")
+
+	v, err = GitVersion(dir)
+	require.NoError(t, err)
+	require.Equal(t, "1.4.0", v.String())
+
+	commit("3")
+	run("tag", "not-semver")
+
+	v, err = GitVersion(dir)
+	require.NoError(t, err)
+	require.Regexp(t, `^1\.4\.0-1-g[0-9a-f]{7,}$`, v.String())
+
+	run("tag", "release-2020.01.02")
+	run("tag", "-a", "-m", "v2020", "v2020.01.02")
+
+	v, err = GitVersion(dir)
+	require.NoError(t, err)
+	require.Regexp(t, `^1\.4\.0-1-g[0-9a-f]{7,}$`, v.String())
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("4"), 0644))
+
+	v, err = GitVersion(dir)
+	require.NoError(t, err)
+	require.Regexp(t, `^1\.4\.0-1-g[0-9a-f]{7,}-dirty$`, v.String())
+
+	p := &Project{Name: "helmx", VersionFrom: VersionFromGit}
+	require.NoError(t, p.ResolveVersion(dir))
+	require.Equal(t, 1, p.Version.Major)
+	require.Equal(t, 4, p.Version.Minor)
+
+	p.VersionFrom = "svn"
+	require.Error(t, p.ResolveVersion(dir))
+}
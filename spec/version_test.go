@@ -80,3 +80,113 @@ func TestVersion_Yaml(t *testing.T) {
 		require.Equal(t, "feat-1.2.3-xxx", v.Version.String())
 	})
 }
+
+func TestVersion_SemVer(t *testing.T) {
+	t.Run("pre-release and build", func(t *testing.T) {
+		v, err := ParseVersion("1.2.3-rc.1+build.5")
+		require.NoError(t, err)
+		require.Equal(t, Version{Major: 1, Minor: 2, Patch: 3, Suffix: "rc.1", Build: "build.5"}, *v)
+		require.Equal(t, []string{"rc", "1"}, v.PreRelease())
+		require.Equal(t, "1.2.3-rc.1+build.5", v.String())
+
+		v, err = ParseVersion("feat-x-1.2.3-rc-1")
+		require.NoError(t, err)
+		require.Equal(t, "feat-x", v.Prefix)
+		require.Equal(t, "rc-1", v.Suffix)
+		require.Equal(t, "feat-x-1.2.3-rc-1", v.String())
+	})
+
+	t.Run("large numbers", func(t *testing.T) {
+		v, err := ParseVersion("1.2.1024")
+		require.NoError(t, err)
+		require.Equal(t, 1024, v.Patch)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"", "1.2", "01.2.3", "1.2.3-rc.01", "1.2.3-rc..1", "1.2.3+", "v1.2.3-"} {
+			_, err := ParseVersion(s)
+			require.Error(t, err, s)
+		}
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		// from SemVer 2.0 spec
+		ordered := []string{
+			"1.0.0-alpha",
+			"1.0.0-alpha.1",
+			"1.0.0-alpha.beta",
+			"1.0.0-beta",
+			"1.0.0-beta.2",
+			"1.0.0-beta.11",
+			"1.0.0-rc.1",
+			"1.0.0",
+			"1.0.1",
+			"1.1.0",
+			"2.0.0",
+		}
+
+		for i := 1; i < len(ordered); i++ {
+			a, _ := ParseVersion(ordered[i-1])
+			b, _ := ParseVersion(ordered[i])
+			require.True(t, a.LessThan(*b), "%s < %s", a, b)
+			require.Equal(t, 1, b.Compare(*a))
+		}
+
+		a, _ := ParseVersion("feat-1.0.0+build.1")
+		b, _ := ParseVersion("1.0.0+build.2")
+		require.Equal(t, 0, a.Compare(*b))
+	})
+}
+
+func TestVersionConstraint(t *testing.T) {
+	cases := []struct {
+		constraint string
+		matched    []string
+		unmatched  []string
+	}{
+		{">=1.2 <2", []string{"1.2.0", "1.9.9", "1.2.3-rc.1"}, []string{"1.1.9", "2.0.0"}},
+		{"1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.1.0"}},
+		{"=1.2.3", []string{"1.2.3", "1.2.3+build"}, []string{"1.2.4", "1.2.3-rc.1"}},
+		{"!=1.2", []string{"1.1.9", "1.3.0"}, []string{"1.2.0", "1.2.5"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"2.0.0", "1.2.2"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"<1 || >=2.1.0-rc.1", []string{"0.9.0", "2.1.0-rc.1", "3.0.0"}, []string{"1.0.0", "2.0.0"}},
+	}
+
+	for _, c := range cases {
+		constraint, err := ParseVersionConstraint(c.constraint)
+		require.NoError(t, err, c.constraint)
+
+		for _, s := range c.matched {
+			v, err := ParseVersion(s)
+			require.NoError(t, err)
+			require.True(t, constraint.Check(*v), "%s should match %s", s, c.constraint)
+		}
+
+		for _, s := range c.unmatched {
+			v, err := ParseVersion(s)
+			require.NoError(t, err)
+			require.False(t, constraint.Check(*v), "%s should not match %s", s, c.constraint)
+		}
+	}
+
+	t.Run("string", func(t *testing.T) {
+		constraint, _ := ParseVersionConstraint(">=1.2   <2||^3.0.0")
+		require.Equal(t, ">=1.2 <2 || ^3.0.0", constraint.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"", ">=1.2 ||", "=>1.2", "1.2-rc.1", "~x"} {
+			_, err := ParseVersionConstraint(s)
+			require.Error(t, err, s)
+		}
+	})
+}
+
+func TestProjectDefaultImageTag(t *testing.T) {
+	v, _ := ParseVersion("1.2.3-rc.1+build.5")
+	require.Equal(t, "~helmx/helmx:1.2.3-rc.1_build.5", Project{Name: "helmx", Group: "helmx", Version: *v}.DefaultImageTag())
+}
@@ -0,0 +1,74 @@
+package tmpl
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/go-courier/helmx/constants"
+	"github.com/go-courier/helmx/kubetypes"
+	"github.com/go-courier/helmx/spec"
+)
+
+func ToKubeExternalServices(s spec.Spec) []kubetypes.KubeExternalService {
+	services, _ := ToKubeExternalServicesE(s)
+	return services
+}
+
+// ToKubeExternalServicesE converts external upstreams, like db=postgres.internal.example.com:5432,
+// to services named by upstream.
+//
+// Upstream of host is converted to ExternalName service,
+// and upstream of ip is converted to service without selector and EndpointSlice of the ip.
+func ToKubeExternalServicesE(s spec.Spec) ([]kubetypes.KubeExternalService, error) {
+	upstreams, err := s.ResolveUpstreams()
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]kubetypes.KubeExternalService, 0)
+
+	for _, u := range upstreams {
+		if !u.IsExternal() {
+			continue
+		}
+
+		es := kubetypes.KubeExternalService{}
+		es.Metadata = ToKubeObjectMeta(s, spec.KindExternalService, u.Name)
+
+		var port *kubetypes.KubeServicePort
+
+		if u.Port != 0 {
+			port = &kubetypes.KubeServicePort{
+				Name:     u.Scheme + "-" + strconv.FormatUint(uint64(u.Port), 10),
+				Port:     u.Port,
+				Protocol: constants.ProtocolTCP,
+			}
+			es.Spec.Ports = []kubetypes.KubeServicePort{*port}
+		}
+
+		if !u.IsIP() {
+			es.Spec.Type = kubetypes.ServiceTypeExternalName
+			es.Spec.ExternalName = u.Host
+			services = append(services, es)
+			continue
+		}
+
+		es.Spec.Type = kubetypes.ServiceTypeClusterIP
+
+		slice := &kubetypes.KubeEndpointSlice{}
+		slice.Metadata = ToKubeObjectMeta(s, spec.KindEndpointSlice, u.Name).WithLabel("kubernetes.io/service-name", u.Name)
+
+		slice.AddressType = kubetypes.AddressTypeIPv4
+		if net.ParseIP(u.Host).To4() == nil {
+			slice.AddressType = kubetypes.AddressTypeIPv6
+		}
+
+		slice.Endpoints = []kubetypes.Endpoint{{Addresses: []string{u.Host}}}
+		slice.Ports = []kubetypes.EndpointPort{{Name: port.Name, Port: port.Port, Protocol: port.Protocol}}
+
+		es.EndpointSlice = slice
+		services = append(services, es)
+	}
+
+	return services, nil
+}
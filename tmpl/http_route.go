@@ -0,0 +1,189 @@
+package tmpl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-courier/helmx/kubetypes"
+	"github.com/go-courier/helmx/spec"
+)
+
+var ErrMissingGateway = errors.New("missing gateway of target")
+
+func ToKubeHTTPRoutes(s spec.Spec) []kubetypes.KubeHTTPRoute {
+	routes, _ := ToKubeHTTPRoutesE(s)
+	return routes
+}
+
+// ToKubeHTTPRoutesE converts ingress rules to HTTPRoutes, one for each host.
+//
+// Routes of hosts with tls attach to the https listener of the gateway,
+// and the others attach to the http listener.
+// Rewrite of ingress options is converted to URLRewrite filter, other options are ignored.
+func ToKubeHTTPRoutesE(s spec.Spec) ([]kubetypes.KubeHTTPRoute, error) {
+	if s.Service == nil {
+		return nil, ErrMissingService
+	}
+
+	if s.Target == nil || s.Target.Gateway == nil {
+		return nil, ErrMissingGateway
+	}
+
+	routes := make([]kubetypes.KubeHTTPRoute, 0)
+	indexes := map[string]int{}
+
+	routeOf := func(host string) *kubetypes.KubeHTTPRoute {
+		idx, ok := indexes[host]
+		if !ok {
+			route := kubetypes.KubeHTTPRoute{}
+
+			name := s.Project.FullName()
+			if len(routes) > 0 {
+				name = spec.JoinName(name, strconv.Itoa(len(routes)))
+			}
+			route.Metadata = ToKubeObjectMeta(s, spec.KindHTTPRoute, name)
+
+			route.Spec.ParentRefs = []kubetypes.ParentReference{
+				{
+					Namespace:   s.Target.Gateway.Namespace,
+					Name:        s.Target.Gateway.Name,
+					SectionName: s.Target.Gateway.SectionName(hasTLS(s, host)),
+				},
+			}
+
+			if host != "" {
+				route.Spec.Hostnames = []string{host}
+			}
+
+			idx = len(routes)
+			indexes[host] = idx
+			routes = append(routes, route)
+		}
+		return &routes[idx]
+	}
+
+	for _, r := range s.Service.Ingresses {
+		host := r.Host
+		if r.IsWildcardHost() {
+			host = ""
+		}
+
+		rule, err := toKubeHTTPRouteRule(s, r)
+		if err != nil {
+			return nil, fmt.Errorf("ingress %s: %w", r, err)
+		}
+
+		route := routeOf(host)
+		route.Spec.Rules = append(route.Spec.Rules, rule)
+	}
+
+	if s.Service.IngressDefaultBackend != nil {
+		backendRef, err := toKubeHTTPBackendRef(s, *s.Service.IngressDefaultBackend)
+		if err != nil {
+			return nil, fmt.Errorf("ingress default backend: %w", err)
+		}
+
+		route := routeOf("")
+		route.Spec.Rules = append(route.Spec.Rules, kubetypes.HTTPRouteRule{
+			BackendRefs: []kubetypes.HTTPBackendRef{backendRef},
+		})
+	}
+
+	return routes, nil
+}
+
+func hasTLS(s spec.Spec, host string) bool {
+	for _, t := range s.Service.TLS {
+		if len(t.Hosts) == 0 {
+			return true
+		}
+		for _, h := range t.Hosts {
+			if h == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func toKubeHTTPRouteRule(s spec.Spec, r spec.IngressRule) (kubetypes.HTTPRouteRule, error) {
+	match := &kubetypes.HTTPPathMatch{
+		Type:  kubetypes.HTTPPathMatchPathPrefix,
+		Value: r.Path,
+	}
+
+	if r.PathType == kubetypes.PathTypeExact {
+		match.Type = kubetypes.HTTPPathMatchExact
+	}
+
+	if match.Value == "" {
+		match.Value = "/"
+	}
+
+	rule := kubetypes.HTTPRouteRule{
+		Matches: []kubetypes.HTTPRouteMatch{{Path: match}},
+	}
+
+	opts := spec.IngressOptions{}
+	if s.Service.IngressOptions != nil {
+		opts = *s.Service.IngressOptions
+	}
+	opts = opts.Merge(r.Options)
+
+	if opts.Rewrite != "" {
+		modifier := &kubetypes.HTTPPathModifier{
+			Type:               "ReplacePrefixMatch",
+			ReplacePrefixMatch: opts.Rewrite,
+		}
+
+		if match.Type == kubetypes.HTTPPathMatchExact {
+			modifier = &kubetypes.HTTPPathModifier{
+				Type:            "ReplaceFullPath",
+				ReplaceFullPath: opts.Rewrite,
+			}
+		}
+
+		rule.Filters = append(rule.Filters, kubetypes.HTTPRouteFilter{
+			Type:       "URLRewrite",
+			URLRewrite: &kubetypes.HTTPURLRewriteFilter{Path: modifier},
+		})
+	}
+
+	backend := spec.IngressBackend{Port: r.Port, PortName: r.PortName}
+	if r.Backend != nil {
+		backend = *r.Backend
+	}
+
+	backendRef, err := toKubeHTTPBackendRef(s, backend)
+	if err != nil {
+		return rule, err
+	}
+
+	rule.BackendRefs = []kubetypes.HTTPBackendRef{backendRef}
+
+	return rule, nil
+}
+
+// toKubeHTTPBackendRef converts backend to backendRef,
+// which port should be a number, so port name is only resolvable for the service itself.
+func toKubeHTTPBackendRef(s spec.Spec, b spec.IngressBackend) (kubetypes.HTTPBackendRef, error) {
+	ref := kubetypes.HTTPBackendRef{
+		Name: b.ServiceName,
+		Port: b.Port,
+	}
+
+	if ref.Name == "" {
+		ref.Name = s.Project.FullName()
+	}
+
+	if b.PortName != "" {
+		p, ok := s.Service.PortOf(0, b.PortName)
+		if b.ServiceName != "" || !ok {
+			return ref, fmt.Errorf("unresolvable port name %q of service %s", b.PortName, ref.Name)
+		}
+		ref.Port = p.Port
+	}
+
+	return ref, nil
+}
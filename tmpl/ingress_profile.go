@@ -0,0 +1,83 @@
+package tmpl
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-courier/helmx/spec"
+)
+
+// IngressProfile maps ingress options to annotations of an ingress controller
+type IngressProfile func(opts spec.IngressOptions) (map[string]string, error)
+
+var (
+	ingressProfilesMu sync.RWMutex
+	ingressProfiles   = map[string]IngressProfile{
+		"nginx":   NginxIngressProfile,
+		"traefik": TraefikIngressProfile,
+	}
+)
+
+// RegisterIngressProfile registers or replaces the profile of ingress controller
+func RegisterIngressProfile(controller string, profile IngressProfile) {
+	ingressProfilesMu.Lock()
+	defer ingressProfilesMu.Unlock()
+
+	ingressProfiles[controller] = profile
+}
+
+// UnregisterIngressProfile removes the profile of ingress controller
+func UnregisterIngressProfile(controller string) {
+	ingressProfilesMu.Lock()
+	defer ingressProfilesMu.Unlock()
+
+	delete(ingressProfiles, controller)
+}
+
+func ingressProfileOf(controller string) (IngressProfile, bool) {
+	ingressProfilesMu.RLock()
+	defer ingressProfilesMu.RUnlock()
+
+	profile, ok := ingressProfiles[controller]
+	return profile, ok
+}
+
+func NginxIngressProfile(opts spec.IngressOptions) (map[string]string, error) {
+	if len(opts.Middlewares) > 0 {
+		return nil, fmt.Errorf("nginx: middlewares are not supported")
+	}
+
+	annotations := map[string]string{}
+
+	if opts.Rewrite != "" {
+		annotations["nginx.ingress.kubernetes.io/rewrite-target"] = opts.Rewrite
+	}
+	if opts.BodySize != "" {
+		annotations["nginx.ingress.kubernetes.io/proxy-body-size"] = opts.BodySize
+	}
+	if opts.BackendProtocol != "" && opts.BackendProtocol != "HTTP" {
+		annotations["nginx.ingress.kubernetes.io/backend-protocol"] = opts.BackendProtocol
+	}
+
+	return annotations, nil
+}
+
+// TraefikIngressProfile supports middlewares only.
+// Backend protocol is detected by traefik from app protocol of service port.
+func TraefikIngressProfile(opts spec.IngressOptions) (map[string]string, error) {
+	if opts.Rewrite != "" {
+		return nil, fmt.Errorf("traefik: rewrite is not supported, use a middleware instead")
+	}
+	if opts.BodySize != "" {
+		return nil, fmt.Errorf("traefik: bodySize is not supported, use a middleware instead")
+	}
+
+	annotations := map[string]string{}
+
+	if len(opts.Middlewares) > 0 {
+		annotations["traefik.ingress.kubernetes.io/router.middlewares"] = strings.Join(opts.Middlewares, ",")
+	}
+
+	return annotations, nil
+}
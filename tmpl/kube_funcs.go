@@ -1,6 +1,7 @@
 package tmpl
 
 import (
+    "errors"
     "fmt"
     "sort"
     "strconv"
@@ -11,47 +12,97 @@ import (
     "github.com/go-courier/helmx/spec"
 )
 
+// KubeFuncs use the error-returning ToKube*E variants,
+// so errors in spec are surfaced as template execution errors
 var KubeFuncs = template.FuncMap{
-    "toKubeIngressSpec":    ToKubeIngressSpec,
-    "toKubeServiceSpec":    ToKubeServiceSpec,
-    "toKubeDeploymentSpec": ToKubeDeploymentSpec,
-    "toKubeJobSpec":        ToKubeJobSpec,
-    "toKubeCronJobSpec":    ToKubeCronJobSpec,
-    "toKubeRoleRules":      ToKubeRoleRoles,
+    "toKubeIngressSpec":        ToKubeIngressSpecE,
+    "toKubeIngresses":          ToKubeIngressesE,
+    "toKubeIngressAnnotations": ToKubeIngressAnnotationsE,
+    "toKubeHTTPRoutes":         ToKubeHTTPRoutesE,
+    "toKubeNetworkPolicySpec":  ToKubeNetworkPolicySpecE,
+    "toKubeExternalServices":   ToKubeExternalServicesE,
+    "toKubeServiceSpec":        ToKubeServiceSpecE,
+    "toKubeDeploymentSpec":     ToKubeDeploymentSpecE,
+    "toKubeJobSpec":            ToKubeJobSpecE,
+    "toKubeCronJobSpec":        ToKubeCronJobSpecE,
+    "toKubeRoleRules":          ToKubeRoleRolesE,
+    "toKubeObjectMeta":         ToKubeObjectMeta,
+    "toKubeNamespace":          ToKubeNamespaceE,
+    "toKubeName":               spec.JoinName,
+    "toKubeNameWithMaxLength":  spec.JoinNameWithMaxLength,
+}
+
+var (
+    ErrMissingService = errors.New("missing service")
+)
+
+// ToKubeObjectMeta returns metadata of object of the kind with spec namespace, labels and annotations,
+// kind should be one of spec.Kind*, like service
+func ToKubeObjectMeta(s spec.Spec, kind string, name string) kubetypes.KubeObjectMeta {
+    meta := s.ObjectMetaOf(kind)
+
+    m := kubetypes.KubeObjectMeta{
+        Name:        name,
+        Labels:      meta.Labels,
+        Annotations: meta.Annotations,
+    }
+
+    // Namespace is cluster-scoped
+    if kind != spec.KindNamespace {
+        m.Namespace = s.ResolveNamespace()
+    }
+
+    return m
 }
 
 func ToKubeServiceSpec(s spec.Spec) kubetypes.KubeServiceSpec {
+    ss, _ := ToKubeServiceSpecE(s)
+    return ss
+}
+
+func ToKubeServiceSpecE(s spec.Spec) (kubetypes.KubeServiceSpec, error) {
     ss := kubetypes.KubeServiceSpec{
         Type: kubetypes.ServiceTypeClusterIP,
     }
 
+    if s.Service == nil {
+        return ss, ErrMissingService
+    }
+
+    nodePortRange := s.Target.ResolveNodePortRange()
+
+    if err := s.Service.CheckPorts(nodePortRange); err != nil {
+        return ss, err
+    }
+
     if s.Service.Headless {
         ss.ClusterIP = new(string)
         *ss.ClusterIP = "None"
     }
 
     for _, port := range s.Service.Ports {
-        appProtocol := port.AppProtocol
+        p := kubetypes.KubeServicePort{
+            Name:        port.ServicePortName(),
+            Port:        port.Port,
+            TargetPort:  kubetypes.FromInt(int(port.ContainerPort)),
+            AppProtocol: port.AppProtocol,
+        }
 
-        if appProtocol == "" {
-            appProtocol = "http"
+        // target container port by name when it is named
+        if name := s.Service.ContainerPortNameOf(port); name != "" {
+            p.TargetPort = kubetypes.FromString(name)
         }
 
-        p := kubetypes.KubeServicePort{
-            Name:       fmt.Sprintf("%s-%d", appProtocol, port.Port),
-            Port:       port.Port,
-            TargetPort: port.ContainerPort,
+        if port.IsLoadBalancer {
+            ss.Type = kubetypes.ServiceTypeLoadBalancer
         }
 
         if port.IsNodePort {
-            ss.Type = kubetypes.ServiceTypeNodePort
-            if port.NodePort != 0 {
-                p.Name = "np-" + fmt.Sprintf("%s-%d", appProtocol, port.NodePort)
-            } else {
-                p.Name = fmt.Sprintf("%s-%d", appProtocol, port.Port)
+            if ss.Type != kubetypes.ServiceTypeLoadBalancer {
+                ss.Type = kubetypes.ServiceTypeNodePort
             }
 
-            if port.NodePort >= 20000 && port.NodePort <= 40000 {
+            if nodePortRange.Contains(port.NodePort) {
                 p.NodePort = port.NodePort
             }
         }
@@ -64,64 +115,143 @@ func ToKubeServiceSpec(s spec.Spec) kubetypes.KubeServiceSpec {
 
         ss.Ports = append(ss.Ports, p)
     }
-    return ss
+
+    ss.ServiceOpts = s.Service.ServiceOpts
+
+    if len(ss.LoadBalancerSourceRanges) > 0 && ss.Type != kubetypes.ServiceTypeLoadBalancer {
+        return ss, fmt.Errorf("loadBalancerSourceRanges only works with service of type %s, but got %s", kubetypes.ServiceTypeLoadBalancer, ss.Type)
+    }
+
+    if ss.ExternalTrafficPolicy != "" && ss.Type != kubetypes.ServiceTypeLoadBalancer && ss.Type != kubetypes.ServiceTypeNodePort {
+        return ss, fmt.Errorf("externalTrafficPolicy only works with service of type %s or %s, but got %s", kubetypes.ServiceTypeNodePort, kubetypes.ServiceTypeLoadBalancer, ss.Type)
+    }
+
+    return ss, nil
 }
 
 func ToKubeDeploymentSpec(s spec.Spec) kubetypes.KubeDeploymentSpec {
+    ds, _ := ToKubeDeploymentSpecE(s)
+    return ds
+}
+
+func ToKubeDeploymentSpecE(s spec.Spec) (kubetypes.KubeDeploymentSpec, error) {
     ds := kubetypes.KubeDeploymentSpec{}
 
-    ds.Template.Metadata.Labels = map[string]string{
-        "srv": s.Project.FullName(),
+    if s.Service == nil {
+        return ds, ErrMissingService
     }
 
-    if s.Labels != nil {
-        for k, v := range s.Labels {
-            ds.Template.Metadata.Labels[k] = v
-        }
+    podMeta := s.PodMeta()
+
+    ds.Template.Metadata.Labels = map[string]string{}
+    for k, v := range podMeta.Labels {
+        ds.Template.Metadata.Labels[k] = v
     }
+    // srv should not be overwritten, which is used by selectors
+    ds.Template.Metadata.Labels["srv"] = s.Project.FullName()
+    ds.Template.Metadata.Annotations = podMeta.Annotations
 
     ds.DeploymentOpts = s.Service.DeploymentOpts
-    ds.Template.Spec = ToKubePodSpec(s, s.Service.Pod)
 
-    return ds
+    podSpec, err := ToKubePodSpecE(s, s.Service.Pod)
+    if err != nil {
+        return ds, fmt.Errorf("service: %w", err)
+    }
+    ds.Template.Spec = podSpec
+
+    return ds, nil
 }
 
 func ToKubeJobSpec(s spec.Spec, job spec.Job) kubetypes.KubeJobSpec {
+    js, _ := ToKubeJobSpecE(s, job)
+    return js
+}
+
+func ToKubeJobSpecE(s spec.Spec, job spec.Job) (kubetypes.KubeJobSpec, error) {
     js := kubetypes.KubeJobSpec{}
     js.JobOpts = job.JobOpts
-    js.Template.Spec = ToKubePodSpec(s, job.Pod)
-    return js
+
+    podMeta := s.PodMeta()
+    js.Template.Metadata.Labels = podMeta.Labels
+    js.Template.Metadata.Annotations = podMeta.Annotations
+
+    podSpec, err := ToKubePodSpecE(s, job.Pod)
+    if err != nil {
+        return js, fmt.Errorf("job: %w", err)
+    }
+    js.Template.Spec = podSpec
+
+    return js, nil
 }
 
 func ToKubeCronJobSpec(s spec.Spec, job spec.Job) kubetypes.KubeCronJobSpec {
+    js, _ := ToKubeCronJobSpecE(s, job)
+    return js
+}
+
+func ToKubeCronJobSpecE(s spec.Spec, job spec.Job) (kubetypes.KubeCronJobSpec, error) {
     js := kubetypes.KubeCronJobSpec{}
     if job.Cron != nil {
         js.CronJobOpts = *job.Cron
     }
-    js.Template.Spec = ToKubeJobSpec(s, job)
-    return js
+
+    jobSpec, err := ToKubeJobSpecE(s, job)
+    if err != nil {
+        return js, err
+    }
+    js.Template.Spec = jobSpec
+
+    jobMeta := s.ObjectMetaOf(spec.KindJob)
+    js.Template.Metadata.Labels = jobMeta.Labels
+    js.Template.Metadata.Annotations = jobMeta.Annotations
+
+    return js, nil
 }
 
 func ToKubePodSpec(s spec.Spec, pod spec.Pod) kubetypes.KubePodSpec {
+    ps, _ := ToKubePodSpecE(s, pod)
+    return ps
+}
+
+func ToKubePodSpecE(s spec.Spec, pod spec.Pod) (kubetypes.KubePodSpec, error) {
     ps := kubetypes.KubePodSpec{}
 
     ps.KubeVolumes = ToKubeVolumes(s)
     ps.KubeTolerations = ToKubeTolerations(s)
 
-    ps.KubeInitContainers = ToKubeInitContainers(s, pod)
-    ps.KubeContainers = ToKubeContainers(s, pod)
+    initContainers, err := ToKubeInitContainersE(s, pod)
+    if err != nil {
+        return ps, err
+    }
+    ps.KubeInitContainers = initContainers
+
+    containers, err := ToKubeContainersE(s, pod)
+    if err != nil {
+        return ps, err
+    }
+    ps.KubeContainers = containers
+
     ps.KubeImagePullSecrets = ToKubeImagePullSecrets(s, pod)
     ps.PodOpts = pod.PodOpts
     ps.HostAliases = ToKubeHosts(s)
     ps.KubeTopologySpreadConstraints = ToKubeTopologySpreadConstraints(pod)
     ps.KubeAffinity = ToKubeAffinity(pod)
 
-    return ps
+    return ps, nil
 }
 
 func ToKubeRoleRoles(s spec.Spec) []kubetypes.KubeRoleRule {
+    rules, _ := ToKubeRoleRolesE(s)
+    return rules
+}
+
+func ToKubeRoleRolesE(s spec.Spec) ([]kubetypes.KubeRoleRule, error) {
     rules := make([]kubetypes.KubeRoleRule, 0)
 
+    if s.Service == nil {
+        return rules, ErrMissingService
+    }
+
     for _, r := range s.Service.ServiceAccountRoleRules {
         rule := kubetypes.KubeRoleRule{
             ApiGroups:     r.ApiGroups,
@@ -133,29 +263,26 @@ func ToKubeRoleRoles(s spec.Spec) []kubetypes.KubeRoleRule {
         rules = append(rules, rule)
     }
 
-    return rules
+    return rules, nil
 }
 
 func ToKubeIngressSpec(s spec.Spec) kubetypes.KubeIngressSpec {
+    is, _ := ToKubeIngressSpecE(s)
+    return is
+}
+
+func ToKubeIngressSpecE(s spec.Spec) (kubetypes.KubeIngressSpec, error) {
     is := kubetypes.KubeIngressSpec{}
 
-    for _, r := range s.Service.Ingresses {
-        rule := kubetypes.IngressRule{
-            Host: r.Host,
-            HTTP: &kubetypes.HTTPIngressRuleValue{
-                Paths: []kubetypes.HTTPIngressPath{
-                    {
-                        Path: r.Path,
-                        Backend: kubetypes.IngressBackend{
-                            ServiceName: s.Project.FullName(),
-                            ServicePort: r.Port,
-                        },
-                    },
-                },
-            },
-        }
+    if s.Service == nil {
+        return is, ErrMissingService
+    }
+
+    is.IngressOpts = toKubeIngressOpts(s)
+    setKubeIngressDefaultBackend(s, &is)
 
-        is.Rules = append(is.Rules, rule)
+    for _, r := range s.Service.Ingresses {
+        is.Rules = appendKubeIngressRule(is.Rules, toKubeIngressRule(s, r))
     }
 
     for _, t := range s.Service.TLS {
@@ -165,7 +292,252 @@ func ToKubeIngressSpec(s spec.Spec) kubetypes.KubeIngressSpec {
         is.TLS = append(is.TLS, tls)
     }
 
-    return is
+    return is, nil
+}
+
+func toKubeIngressOpts(s spec.Spec) kubetypes.IngressOpts {
+    // ingressClassName is not supported by extensions/v1beta1
+    if s.Target.IngressAPIVersion() == spec.IngressAPIVersionExtensionsV1beta1 {
+        return kubetypes.IngressOpts{}
+    }
+    return s.Service.IngressOpts
+}
+
+func toKubeIngressBackend(s spec.Spec, b spec.IngressBackend) kubetypes.IngressBackend {
+    backend := kubetypes.IngressBackend{}
+
+    serviceName := b.ServiceName
+    if serviceName == "" {
+        serviceName = s.Project.FullName()
+    }
+
+    if s.Target.IsLegacyIngress() {
+        backend.ServiceName = serviceName
+        backend.ServicePort = kubetypes.FromInt(int(b.Port))
+        if b.PortName != "" {
+            backend.ServicePort = kubetypes.FromString(b.PortName)
+        }
+    } else {
+        backend.Service = &kubetypes.IngressServiceBackend{
+            Name: serviceName,
+            Port: kubetypes.ServiceBackendPort{
+                Name:   b.PortName,
+                Number: b.Port,
+            },
+        }
+        if b.PortName != "" {
+            backend.Service.Port.Number = 0
+        }
+    }
+
+    return backend
+}
+
+func setKubeIngressDefaultBackend(s spec.Spec, is *kubetypes.KubeIngressSpec) {
+    if s.Service.IngressDefaultBackend == nil {
+        return
+    }
+
+    backend := toKubeIngressBackend(s, *s.Service.IngressDefaultBackend)
+
+    if s.Target.IsLegacyIngress() {
+        is.Backend = &backend
+    } else {
+        is.DefaultBackend = &backend
+    }
+}
+
+func toKubeIngressRule(s spec.Spec, r spec.IngressRule) kubetypes.IngressRule {
+    path := kubetypes.HTTPIngressPath{
+        Path:     r.Path,
+        PathType: r.PathType,
+    }
+
+    backend := spec.IngressBackend{Port: r.Port, PortName: r.PortName}
+    if r.Backend != nil {
+        backend = *r.Backend
+    }
+    path.Backend = toKubeIngressBackend(s, backend)
+
+    if s.Target.IsLegacyIngress() {
+        if s.Target.IngressAPIVersion() == spec.IngressAPIVersionExtensionsV1beta1 {
+            path.PathType = ""
+        }
+    } else {
+        // pathType and path are required since networking.k8s.io/v1
+        if path.Path == "" {
+            path.Path = "/"
+        }
+        if path.PathType == "" {
+            path.PathType = kubetypes.PathTypePrefix
+        }
+    }
+
+    rule := kubetypes.IngressRule{
+        HTTP: &kubetypes.HTTPIngressRuleValue{
+            Paths: []kubetypes.HTTPIngressPath{path},
+        },
+    }
+
+    if !r.IsWildcardHost() {
+        rule.Host = r.Host
+    }
+
+    return rule
+}
+
+// appendKubeIngressRule merges paths of the rule into the rule with same host
+func appendKubeIngressRule(rules []kubetypes.IngressRule, rule kubetypes.IngressRule) []kubetypes.IngressRule {
+    for i := range rules {
+        if rules[i].Host == rule.Host {
+            rules[i].HTTP.Paths = append(rules[i].HTTP.Paths, rule.HTTP.Paths...)
+            return rules
+        }
+    }
+    return append(rules, rule)
+}
+
+func ToKubeIngresses(s spec.Spec) []kubetypes.KubeIngress {
+    ingresses, _ := ToKubeIngressesE(s)
+    return ingresses
+}
+
+// ToKubeIngressesE converts ingress rules to Ingress objects.
+// Annotations apply to the whole Ingress object,
+// so rules with different annotations are split into Ingress objects named <fullName>--<n>.
+func ToKubeIngressesE(s spec.Spec) ([]kubetypes.KubeIngress, error) {
+    if s.Service == nil {
+        return nil, ErrMissingService
+    }
+
+    ingresses := make([]kubetypes.KubeIngress, 0)
+    indexes := map[string]int{}
+
+    for _, r := range s.Service.Ingresses {
+        annotations, err := ToKubeIngressAnnotationsE(s, r)
+        if err != nil {
+            return nil, fmt.Errorf("ingress %s: %w", r, err)
+        }
+
+        key := toJson(annotations)
+
+        idx, ok := indexes[key]
+        if !ok {
+            ingress := kubetypes.KubeIngress{}
+
+            name := s.Project.FullName()
+            if len(ingresses) > 0 {
+                name = spec.JoinName(name, strconv.Itoa(len(ingresses)))
+            }
+
+            ingress.Metadata = ToKubeObjectMeta(s, spec.KindIngress, name)
+            for k, v := range annotations {
+                ingress.Metadata = ingress.Metadata.WithAnnotation(k, v)
+            }
+
+            ingress.Spec.IngressOpts = toKubeIngressOpts(s)
+
+            idx = len(ingresses)
+            indexes[key] = idx
+            ingresses = append(ingresses, ingress)
+        }
+
+        ingresses[idx].Spec.Rules = appendKubeIngressRule(ingresses[idx].Spec.Rules, toKubeIngressRule(s, r))
+    }
+
+    if s.Service.IngressDefaultBackend != nil {
+        if len(ingresses) == 0 {
+            ingress := kubetypes.KubeIngress{}
+            ingress.Metadata = ToKubeObjectMeta(s, spec.KindIngress, s.Project.FullName())
+            ingress.Spec.IngressOpts = toKubeIngressOpts(s)
+            ingresses = append(ingresses, ingress)
+        }
+        setKubeIngressDefaultBackend(s, &ingresses[0].Spec)
+    }
+
+    for i := range ingresses {
+        for _, t := range s.Service.TLS {
+            if len(ingresses) > 1 && !tlsMatchesRules(t, ingresses[i].Spec.Rules) {
+                continue
+            }
+
+            ingresses[i].Spec.TLS = append(ingresses[i].Spec.TLS, kubetypes.IngressTLS{
+                SecretName: t.SecretName,
+                Hosts:      t.Hosts,
+            })
+        }
+    }
+
+    return ingresses, nil
+}
+
+func tlsMatchesRules(t spec.IngressTLS, rules []kubetypes.IngressRule) bool {
+    if len(t.Hosts) == 0 {
+        return true
+    }
+    for _, host := range t.Hosts {
+        for _, r := range rules {
+            if r.Host == host {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// ToKubeIngressAnnotationsE returns annotations of ingress rule,
+// with options of service and rule mapped by profile of the ingress controller.
+func ToKubeIngressAnnotationsE(s spec.Spec, r spec.IngressRule) (map[string]string, error) {
+    if s.Service == nil {
+        return nil, ErrMissingService
+    }
+
+    opts := spec.IngressOptions{}
+    if s.Service.IngressOptions != nil {
+        opts = *s.Service.IngressOptions
+    }
+    opts = opts.Merge(r.Options)
+
+    annotations := map[string]string{}
+
+    if s.Target.IngressAPIVersion() == spec.IngressAPIVersionExtensionsV1beta1 && s.Service.IngressClassName != "" {
+        annotations["kubernetes.io/ingress.class"] = s.Service.IngressClassName
+    }
+
+    controller := opts.Controller
+    if controller == "" {
+        controller = s.Service.IngressClassName
+    }
+
+    profile, ok := ingressProfileOf(controller)
+    if !ok {
+        if opts.Controller != "" {
+            return nil, fmt.Errorf("unknown ingress controller %q", opts.Controller)
+        }
+        if opts.Rewrite != "" || opts.BodySize != "" || opts.BackendProtocol != "" || len(opts.Middlewares) > 0 {
+            return nil, fmt.Errorf("missing ingress controller to map options")
+        }
+    } else {
+        if opts.BackendProtocol == "" && r.Backend == nil {
+            if p, ok := s.Service.PortOf(r.Port, r.PortName); ok {
+                opts.BackendProtocol = spec.BackendProtocolOf(p.AppProtocol)
+            }
+        }
+
+        profileAnnotations, err := profile(opts)
+        if err != nil {
+            return nil, err
+        }
+        for k, v := range profileAnnotations {
+            annotations[k] = v
+        }
+    }
+
+    for k, v := range opts.Annotations {
+        annotations[k] = v
+    }
+
+    return annotations, nil
 }
 
 func ToKubeEnv(envs spec.EnvsWithValueFrom) kubetypes.KubeEnv {
@@ -194,6 +566,21 @@ func ToKubeEnv(envs spec.EnvsWithValueFrom) kubetypes.KubeEnv {
                     "key":      envs[k].ValueFromSecret.Key,
                     "optional": envs[k].ValueFromSecret.Optional,
                 }
+            } else if envs[k].ValueFromField != nil {
+                kubeEnvVar.ValueFrom["fieldRef"] = map[string]interface{}{
+                    "fieldPath": envs[k].ValueFromField.FieldPath,
+                }
+            } else if envs[k].ValueFromResource != nil {
+                resourceFieldRef := map[string]interface{}{
+                    "resource": envs[k].ValueFromResource.Resource,
+                }
+                if envs[k].ValueFromResource.ContainerName != "" {
+                    resourceFieldRef["containerName"] = envs[k].ValueFromResource.ContainerName
+                }
+                if envs[k].ValueFromResource.Divisor != "" {
+                    resourceFieldRef["divisor"] = envs[k].ValueFromResource.Divisor
+                }
+                kubeEnvVar.ValueFrom["resourceFieldRef"] = resourceFieldRef
             } else {
                 if envs[k].ValueFromConfigMap != nil {
                     kubeEnvVar.ValueFrom["configMapKeyRef"] = map[string]interface{}{
@@ -210,38 +597,101 @@ func ToKubeEnv(envs spec.EnvsWithValueFrom) kubetypes.KubeEnv {
     return e
 }
 
+func ToKubeEnvFrom(envFrom []spec.EnvFrom) kubetypes.KubeEnvFrom {
+    e := kubetypes.KubeEnvFrom{}
+
+    for _, from := range envFrom {
+        source := kubetypes.KubeEnvFromSource{
+            Prefix: from.Prefix,
+        }
+
+        ref := &kubetypes.KubeEnvFromSourceObject{}
+
+        if from.Optional {
+            optional := true
+            ref.Optional = &optional
+        }
+
+        if from.SecretName != "" {
+            ref.Name = from.SecretName
+            source.SecretRef = ref
+        } else {
+            ref.Name = from.ConfigMapName
+            source.ConfigMapRef = ref
+        }
+
+        e.EnvFrom = append(e.EnvFrom, source)
+    }
+
+    return e
+}
+
 func ToKubeInitContainers(s spec.Spec, pod spec.Pod) kubetypes.KubeInitContainers {
+    ss, _ := ToKubeInitContainersE(s, pod)
+    return ss
+}
+
+func ToKubeInitContainersE(s spec.Spec, pod spec.Pod) (kubetypes.KubeInitContainers, error) {
     ss := kubetypes.KubeInitContainers{}
 
+    if pod.WaitForUpstreams != nil {
+        containers, err := toKubeWaitForUpstreamsContainers(s, *pod.WaitForUpstreams)
+        if err != nil {
+            return ss, err
+        }
+        ss.InitContainers = append(ss.InitContainers, containers...)
+    }
+
     for i, c := range pod.Initials {
-        container := ToKubeContainer(s, c)
-        container.Name = container.Name + "-init-" + strconv.FormatInt(int64(i), 10)
+        container, err := ToKubeContainerE(s, c)
+        if err != nil {
+            return ss, fmt.Errorf("initials[%d]: %w", i, err)
+        }
+        container.Name = spec.SafeName(container.Name+"-init-"+strconv.FormatInt(int64(i), 10), spec.MaxNameLength)
 
         ss.InitContainers = append(ss.InitContainers, container)
     }
-    return ss
+    return ss, nil
 }
 
 func ToKubeContainers(s spec.Spec, pod spec.Pod) kubetypes.KubeContainers {
+    kc, _ := ToKubeContainersE(s, pod)
+    return kc
+}
+
+func ToKubeContainersE(s spec.Spec, pod spec.Pod) (kubetypes.KubeContainers, error) {
     kc := kubetypes.KubeContainers{}
 
-    c := ToKubeContainer(s, pod.Container)
+    c, err := ToKubeContainerE(s, pod.Container)
+    if err != nil {
+        return kc, err
+    }
 
     // only service can be ports
     if s.Service != nil {
-        c.KubeContainerPorts = toKubeContainerPorts(s, s.Service.Ports)
+        c.KubeContainerPorts = toKubeContainerPorts(s, s.Service.ContainerPorts())
     }
     kc.Containers = []kubetypes.KubeContainer{c}
 
-    return kc
+    return kc, nil
 }
 
 func ToKubeContainer(s spec.Spec, c spec.Container) kubetypes.KubeContainer {
+    ss, _ := ToKubeContainerE(s, c)
+    return ss
+}
+
+func ToKubeContainerE(s spec.Spec, c spec.Container) (kubetypes.KubeContainer, error) {
     ss := kubetypes.KubeContainer{}
 
     ss.Name = s.Project.FullName()
-    ss.KubeImage.Image = c.ImageTag(s.Project.DefaultImageTag())
-    ss.KubeImage.ImagePullPolicy = c.ImagePullPolicy
+
+    image, err := c.ImageReference(s.Project.DefaultImageTag())
+    if err != nil {
+        return ss, err
+    }
+    ss.KubeImage.Image = image.String()
+    ss.KubeImage.ImagePullPolicy = c.ResolveImagePullPolicy(*image)
 
     ss.WorkingDir = c.WorkingDir
     ss.Command = c.Command
@@ -311,25 +761,37 @@ func ToKubeContainer(s spec.Spec, c spec.Container) kubetypes.KubeContainer {
         }
     }
 
-    if s.Resources != nil {
+    // resources of container take precedence over spec resources by resource type
+    if s.Resources != nil || c.Resources != nil {
         resources := &ss.Resources
 
-        for resourceType, r := range s.Resources {
+        for resourceType, r := range s.Resources.Merge(c.Resources) {
+            if r == nil {
+                continue
+            }
             resources.Add(resourceType, r.RequestString(), r.LimitString())
         }
     }
 
-    if s.Envs != nil {
-        if c.Envs == nil {
-            c.Envs = spec.Envs{}
+    upstreamEnvs, err := s.ResolveUpstreamEnvs()
+    if err != nil {
+        return ss, err
+    }
+
+    if s.Envs != nil || c.Envs != nil || upstreamEnvs != nil {
+        // env vars of upstreams never override explicit envs
+        envsWithValueFrom, err := spec.ParseEnvsWithValueFrom(upstreamEnvs.Merge(c.Envs.Merge(s.Envs)))
+        if err != nil {
+            return ss, err
         }
-        envsWithValueFrom, _ := spec.ParseEnvsWithValueFrom(c.Envs.Merge(s.Envs))
         ss.KubeEnv = ToKubeEnv(envsWithValueFrom)
     }
 
+    ss.KubeEnvFrom = ToKubeEnvFrom(c.EnvFrom)
+
     ss.KubeVolumeMounts = toKubeVolumeMounts(c)
 
-    return ss
+    return ss, nil
 }
 
 func toKubeVolumeMounts(container spec.Container) kubetypes.KubeVolumeMounts {
@@ -385,18 +847,16 @@ func ToKubeImagePullSecrets(s spec.Spec, pod spec.Pod) kubetypes.KubeImagePullSe
     return ss
 }
 
+// ports should be deduplicated by container port and protocol, see spec.Service.ContainerPorts
 func toKubeContainerPorts(s spec.Spec, ports []spec.Port) kubetypes.KubeContainerPorts {
     ss := kubetypes.KubeContainerPorts{}
 
     for _, port := range ports {
         p := kubetypes.KubeContainerPort{
+            Name:          port.ContainerPortName(),
             ContainerPort: port.ContainerPort,
         }
 
-        if p.ContainerPort == 0 {
-            port.ContainerPort = port.Port
-        }
-
         if port.Protocol == "" {
             p.Protocol = constants.ProtocolTCP
         } else {
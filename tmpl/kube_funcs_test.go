@@ -2,15 +2,21 @@ package tmpl_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-courier/helmx/kubetypes"
 	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/go-courier/helmx/constants"
 	"github.com/go-courier/helmx/spec"
 
 	"github.com/go-courier/helmx/tmpl"
+	"github.com/stretchr/testify/require"
 )
 
 func TestToKubeJobSpec(t *testing.T) {
@@ -111,4 +117,359 @@ func TestToKubeEnv(t *testing.T) {
 		kubeEnvs := tmpl.ToKubeEnv(envs)
 		spew.Dump(kubeEnvs)
 	})
+
+	t.Run("field & resource", func(t *testing.T) {
+		envs := make(spec.EnvsWithValueFrom)
+		envs["POD_IP"] = &spec.EnvValue{ValueFromField: &spec.EnvValueFromField{
+			FieldPath: "status.podIP",
+		}}
+		envs["MEMORY_LIMIT"] = &spec.EnvValue{ValueFromResource: &spec.EnvValueFromResource{
+			Resource: "limits.memory",
+			Divisor:  "1Mi",
+		}}
+
+		data, err := yaml.Marshal(tmpl.ToKubeEnv(envs))
+		require.NoError(t, err)
+		require.Equal(t, `env:
+- name: MEMORY_LIMIT
+  valueFrom:
+    resourceFieldRef:
+      divisor: 1Mi
+      resource: limits.memory
+- name: POD_IP
+  valueFrom:
+    fieldRef:
+      fieldPath: status.podIP
+`, string(data))
+	})
+}
+
+func TestToKubeEnvFrom(t *testing.T) {
+	envFrom := tmpl.ToKubeEnvFrom([]spec.EnvFrom{
+		{ConfigMapName: "app-config"},
+		{SecretName: "db-creds", Optional: true, Prefix: "DB_"},
+	})
+
+	data, err := yaml.Marshal(envFrom)
+	require.NoError(t, err)
+	require.Equal(t, `envFrom:
+- configMapRef:
+    name: app-config
+- prefix: DB_
+  secretRef:
+    name: db-creds
+    optional: true
+`, string(data))
+}
+
+func TestToKubeSpecE(t *testing.T) {
+	s := spec.Spec{}
+	s.Project = &spec.Project{Name: "test"}
+	s.Envs = spec.Envs{"DB_PASSWORD": "####db.password.maybe####"}
+
+	t.Run("missing service", func(t *testing.T) {
+		_, err := tmpl.ToKubeDeploymentSpecE(s)
+		require.Equal(t, tmpl.ErrMissingService, err)
+
+		_, err = tmpl.ToKubeServiceSpecE(s)
+		require.Equal(t, tmpl.ErrMissingService, err)
+	})
+
+	t.Run("invalid env reference", func(t *testing.T) {
+		_, err := tmpl.ToKubeJobSpecE(s, spec.Job{})
+		require.True(t, errors.Is(err, spec.ErrInvalidEnvReference))
+		require.Contains(t, err.Error(), "env DB_PASSWORD")
+	})
+
+	t.Run("surfaced through template", func(t *testing.T) {
+		s.Service = &spec.Service{}
+
+		mgr := tmpl.NewTemplateMgr()
+		mgr.AddTemplate("deployment", `{{ toYamlIndent ( toKubeDeploymentSpec . ) "" }}`)
+
+		err := mgr.ExecuteAll(ioutil.Discard, &s)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service: env DB_PASSWORD: invalid env reference")
+	})
+}
+
+func TestToKubeIngressAnnotations(t *testing.T) {
+	s := spec.Spec{
+		Project: &spec.Project{Name: "helmx"},
+		Service: &spec.Service{},
+	}
+
+	rule, _ := spec.ParseIngressRule("http://helmx/api")
+
+	t.Run("traefik middlewares", func(t *testing.T) {
+		s.Service.IngressOptions = &spec.IngressOptions{
+			Controller:  "traefik",
+			Middlewares: []string{"default-auth@kubernetescrd"},
+		}
+
+		annotations, err := tmpl.ToKubeIngressAnnotationsE(s, *rule)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"traefik.ingress.kubernetes.io/router.middlewares": "default-auth@kubernetescrd",
+		}, annotations)
+
+		s.Service.IngressOptions.Rewrite = "/"
+		_, err = tmpl.ToKubeIngressAnnotationsE(s, *rule)
+		require.Error(t, err)
+	})
+
+	t.Run("custom profile", func(t *testing.T) {
+		tmpl.RegisterIngressProfile("custom", func(opts spec.IngressOptions) (map[string]string, error) {
+			return map[string]string{"custom/rewrite": opts.Rewrite}, nil
+		})
+		t.Cleanup(func() {
+			tmpl.UnregisterIngressProfile("custom")
+		})
+
+		s.Service.IngressOptions = &spec.IngressOptions{
+			Controller: "custom",
+			Rewrite:    "/",
+		}
+
+		annotations, err := tmpl.ToKubeIngressAnnotationsE(s, *rule)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"custom/rewrite": "/"}, annotations)
+	})
+
+	t.Run("register while rendering", func(t *testing.T) {
+		t.Cleanup(func() {
+			tmpl.UnregisterIngressProfile("concurrent")
+		})
+
+		s.Service.IngressOptions = &spec.IngressOptions{Controller: "nginx", BodySize: "8m"}
+
+		wg := sync.WaitGroup{}
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				tmpl.RegisterIngressProfile("concurrent", tmpl.NginxIngressProfile)
+			}()
+			go func() {
+				defer wg.Done()
+				_, _ = tmpl.ToKubeIngressAnnotationsE(s, *rule)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("unknown controller", func(t *testing.T) {
+		s.Service.IngressOptions = &spec.IngressOptions{Controller: "unknown"}
+
+		_, err := tmpl.ToKubeIngressAnnotationsE(s, *rule)
+		require.EqualError(t, err, `unknown ingress controller "unknown"`)
+	})
+
+	t.Run("options without controller", func(t *testing.T) {
+		s.Service.IngressOptions = &spec.IngressOptions{BodySize: "8m"}
+
+		_, err := tmpl.ToKubeIngressAnnotationsE(s, *rule)
+		require.Error(t, err)
+	})
+}
+
+func TestToKubeInitContainersWaitForUpstreams(t *testing.T) {
+	s := spec.Spec{
+		Project:   &spec.Project{Name: "helmx"},
+		Upstreams: []string{"redis", "tcp://mysql.db:3306", "http://api:8080/healthz"},
+	}
+
+	pod := spec.Pod{
+		WaitForUpstreams: &spec.WaitForUpstreams{TimeoutSeconds: 30},
+		Initials:         []spec.Container{{Image: spec.Image{Tag: "busybox"}}},
+	}
+
+	initContainers, err := tmpl.ToKubeInitContainersE(s, pod)
+	require.NoError(t, err)
+	require.Len(t, initContainers.InitContainers, 4)
+
+	require.Equal(t, "wait-for-redis", initContainers.InitContainers[0].Name)
+	require.Equal(t, []string{
+		"timeout", "30", "sh", "-c",
+		"until nslookup redis; do echo waiting for redis; sleep 2; done",
+	}, initContainers.InitContainers[0].Command)
+
+	require.Equal(t, "wait-for-mysql-db-3306", initContainers.InitContainers[1].Name)
+	require.Equal(t, []string{
+		"timeout", "30", "sh", "-c",
+		"until nc -z -w 2 mysql.db 3306; do echo waiting for mysql.db:3306; sleep 2; done",
+	}, initContainers.InitContainers[1].Command)
+
+	require.Equal(t, "wait-for-api-8080", initContainers.InitContainers[2].Name)
+	require.Equal(t, []string{
+		"timeout", "30", "sh", "-c",
+		"until wget -q -T 2 -O /dev/null http://api:8080/healthz; do echo waiting for api:8080; sleep 2; done",
+	}, initContainers.InitContainers[2].Command)
+
+	require.Equal(t, "helmx-init-0", initContainers.InitContainers[3].Name)
+
+	s.Upstreams = []string{"http://api:8080/healthz?ready=1&full", "cache=[fd00::10]:6379"}
+
+	initContainers, err = tmpl.ToKubeInitContainersE(s, pod)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"timeout", "30", "sh", "-c",
+		"until wget -q -T 2 -O /dev/null 'http://api:8080/healthz?ready=1&full'; do echo waiting for api:8080; sleep 2; done",
+	}, initContainers.InitContainers[0].Command)
+
+	require.Equal(t, []string{
+		"timeout", "30", "sh", "-c",
+		"until nc -z -w 2 fd00::10 6379; do echo waiting for '[fd00::10]:6379'; sleep 2; done",
+	}, initContainers.InitContainers[1].Command)
+
+	s.Upstreams = []string{"udp://dns:53"}
+	_, err = tmpl.ToKubeInitContainersE(s, pod)
+	require.Error(t, err)
+
+	s.Upstreams = []string{"10.0.0.10"}
+	_, err = tmpl.ToKubeInitContainersE(s, pod)
+	require.Error(t, err)
+}
+
+func TestToKubeContainerWithUpstreamEnvs(t *testing.T) {
+	s := spec.Spec{
+		Project:      &spec.Project{Name: "helmx"},
+		Upstreams:    []string{"redis:6379"},
+		UpstreamEnvs: &spec.UpstreamEnvs{},
+		Envs:         spec.Envs{"REDIS_HOST": "redis.cache"},
+	}
+
+	c, err := tmpl.ToKubeContainerE(s, spec.Container{})
+	require.NoError(t, err)
+	require.Equal(t, []kubetypes.KubeEnvVar{
+		{Name: "REDIS_HOST", Value: "redis.cache"},
+		{Name: "REDIS_PORT", Value: "6379"},
+		{Name: "REDIS_URL", Value: "tcp://redis:6379"},
+	}, c.Env)
+}
+
+func TestToKubeContainersWithSharedContainerPort(t *testing.T) {
+	s := spec.Spec{
+		Project: &spec.Project{Name: "helmx"},
+		Service: &spec.Service{},
+	}
+
+	for _, port := range []string{"80:8080", "8080"} {
+		p, _ := spec.ParsePort(port)
+		s.Service.Ports = append(s.Service.Ports, *p)
+	}
+
+	kc, err := tmpl.ToKubeContainersE(s, s.Service.Pod)
+	require.NoError(t, err)
+	require.Equal(t, []kubetypes.KubeContainerPort{
+		{Name: "http-8080", ContainerPort: 8080, Protocol: constants.ProtocolTCP},
+	}, kc.Containers[0].Ports)
+
+	p, _ := spec.ParsePort("grpc-9000:8080")
+	s.Service.Ports = append(s.Service.Ports, *p)
+
+	ss, err := tmpl.ToKubeServiceSpecE(s)
+	require.NoError(t, err)
+	require.Len(t, ss.Ports, 3)
+	for _, sp := range ss.Ports {
+		require.Equal(t, kubetypes.FromString("http-8080"), sp.TargetPort)
+	}
+}
+
+func TestToKubeServiceSpecWithNodePortRange(t *testing.T) {
+	s := spec.Spec{
+		Project: &spec.Project{Name: "helmx"},
+		Target:  &spec.Target{NodePortRange: &spec.PortRange{Min: 30000, Max: 32767}},
+	}
+
+	p, _ := spec.ParsePort("!30080:80")
+	s.Service = &spec.Service{Ports: []spec.Port{*p}}
+
+	ss, err := tmpl.ToKubeServiceSpecE(s)
+	require.NoError(t, err)
+	require.Equal(t, uint16(30080), ss.Ports[0].NodePort)
+
+	p, _ = spec.ParsePort("!20000:80")
+	s.Service = &spec.Service{Ports: []spec.Port{*p}}
+
+	_, err = tmpl.ToKubeServiceSpecE(s)
+	require.EqualError(t, err, "invalid ports: node port 20000 out of range 30000-32767")
+}
+
+func TestToKubeServiceSpecWithServiceOpts(t *testing.T) {
+	s := spec.Spec{
+		Project: &spec.Project{Name: "helmx"},
+	}
+
+	p, _ := spec.ParsePort("80")
+	s.Service = &spec.Service{Ports: []spec.Port{*p}}
+	s.Service.LoadBalancerSourceRanges = []string{"10.0.0.0/8"}
+
+	_, err := tmpl.ToKubeServiceSpecE(s)
+	require.Error(t, err)
+
+	s.Service.LoadBalancerSourceRanges = nil
+	s.Service.ExternalTrafficPolicy = kubetypes.ServiceTrafficPolicyLocal
+
+	_, err = tmpl.ToKubeServiceSpecE(s)
+	require.Error(t, err)
+
+	p, _ = spec.ParsePort("^80")
+	s.Service.Ports = []spec.Port{*p}
+
+	ss, err := tmpl.ToKubeServiceSpecE(s)
+	require.NoError(t, err)
+	require.Equal(t, kubetypes.ServiceTypeLoadBalancer, ss.Type)
+	require.Equal(t, kubetypes.ServiceTrafficPolicyLocal, ss.ExternalTrafficPolicy)
+}
+
+func TestToKubeIngressesWithMetadata(t *testing.T) {
+	s := spec.Spec{
+		Project:     &spec.Project{Name: "helmx"},
+		Labels:      map[string]string{"team": "infra"},
+		Annotations: map[string]string{"owner": "infra"},
+		Metadata: map[string]spec.ObjectMeta{
+			spec.KindIngress: {Annotations: map[string]string{"owner": "web"}},
+		},
+	}
+
+	r, _ := spec.ParseIngressRule("http://helmx:80?rewrite=/")
+	s.Service = &spec.Service{Ingresses: []spec.IngressRule{*r}}
+	s.Service.IngressOptions = &spec.IngressOptions{Controller: "nginx"}
+
+	ingresses, err := tmpl.ToKubeIngressesE(s)
+	require.NoError(t, err)
+	require.Equal(t, "infra", ingresses[0].Metadata.Labels["team"])
+	require.Equal(t, "helmx", ingresses[0].Metadata.Labels["app.kubernetes.io/name"])
+	require.Equal(t, "web", ingresses[0].Metadata.Annotations["owner"])
+	require.Equal(t, "/", ingresses[0].Metadata.Annotations["nginx.ingress.kubernetes.io/rewrite-target"])
+}
+
+func TestToKubeContainerWithImageReference(t *testing.T) {
+	s := spec.Spec{
+		Project: &spec.Project{Name: "helmx"},
+	}
+
+	c := spec.Container{}
+	c.ImagePullSecret = &spec.ImagePullSecret{}
+	c.Tag = "nginx:1.25"
+	c.Digest = "sha256:" + strings.Repeat("0", 64)
+
+	kc, err := tmpl.ToKubeContainerE(s, c)
+	require.NoError(t, err)
+	require.Equal(t, "nginx:1.25@sha256:"+strings.Repeat("0", 64), kc.Image)
+	require.Equal(t, constants.PullPolicy(""), kc.ImagePullPolicy)
+
+	c.Tag = "nginx"
+	c.Digest = ""
+
+	kc, err = tmpl.ToKubeContainerE(s, c)
+	require.NoError(t, err)
+	require.Equal(t, constants.PullAlways, kc.ImagePullPolicy)
+
+	c.Tag = "Nginx"
+
+	_, err = tmpl.ToKubeContainerE(s, c)
+	require.Error(t, err)
 }
@@ -0,0 +1,23 @@
+package tmpl
+
+import (
+	"github.com/go-courier/helmx/kubetypes"
+	"github.com/go-courier/helmx/spec"
+)
+
+// ToKubeNamespaceE returns the Namespace object with labels of Pod Security admission,
+// nil when namespace should not be created
+func ToKubeNamespaceE(s spec.Spec) (*kubetypes.KubeNamespace, error) {
+	if s.Namespace == nil || !s.Namespace.Create {
+		return nil, nil
+	}
+
+	ns := &kubetypes.KubeNamespace{}
+	ns.Metadata = ToKubeObjectMeta(s, spec.KindNamespace, s.ResolveNamespace())
+
+	for k, v := range s.Namespace.PodSecurityLabels() {
+		ns.Metadata = ns.Metadata.WithLabel(k, v)
+	}
+
+	return ns, nil
+}
@@ -0,0 +1,118 @@
+package tmpl
+
+import (
+	"strings"
+
+	"github.com/go-courier/helmx/constants"
+	"github.com/go-courier/helmx/kubetypes"
+	"github.com/go-courier/helmx/spec"
+)
+
+func ToKubeNetworkPolicySpec(s spec.Spec) kubetypes.KubeNetworkPolicySpec {
+	nps, _ := ToKubeNetworkPolicySpecE(s)
+	return nps
+}
+
+// ToKubeNetworkPolicySpecE converts ports and upstreams of spec to NetworkPolicy of the service pods.
+//
+// Ingress is allowed on the container ports of the service only.
+// Egress is allowed to DNS, pods of upstreams by label srv and CIDRs of spec.NetworkPolicy only.
+// Upstreams as <service>.<namespace> are selected in the namespace,
+// external upstreams of ip are allowed by ip block,
+// and upstreams which are not service names, like external hosts, should be allowed by CIDRs.
+func ToKubeNetworkPolicySpecE(s spec.Spec) (kubetypes.KubeNetworkPolicySpec, error) {
+	nps := kubetypes.KubeNetworkPolicySpec{}
+
+	if s.Service == nil {
+		return nps, ErrMissingService
+	}
+
+	nps.PodSelector.MatchLabels = map[string]string{
+		"srv": s.Project.FullName(),
+	}
+	nps.PolicyTypes = []kubetypes.PolicyType{kubetypes.PolicyTypeIngress, kubetypes.PolicyTypeEgress}
+
+	if len(s.Service.Ports) > 0 {
+		rule := kubetypes.NetworkPolicyIngressRule{}
+
+		for _, p := range s.Service.Ports {
+			port := kubetypes.NetworkPolicyPort{
+				Protocol: p.Protocol,
+				Port:     p.ContainerPort,
+			}
+			if port.Protocol == "" {
+				port.Protocol = constants.ProtocolTCP
+			}
+			rule.Ports = append(rule.Ports, port)
+		}
+
+		nps.Ingress = append(nps.Ingress, rule)
+	}
+
+	nps.Egress = append(nps.Egress, kubetypes.NetworkPolicyEgressRule{
+		Ports: []kubetypes.NetworkPolicyPort{
+			{Protocol: constants.ProtocolUDP, Port: 53},
+			{Protocol: constants.ProtocolTCP, Port: 53},
+		},
+	})
+
+	resolvedUpstreams, err := s.ResolveUpstreams()
+	if err != nil {
+		return nps, err
+	}
+
+	upstreams := kubetypes.NetworkPolicyEgressRule{}
+
+	for _, upstream := range resolvedUpstreams {
+		if upstream.IsExternal() && upstream.IsIP() {
+			cidr := upstream.Host + "/32"
+			if strings.Contains(upstream.Host, ":") {
+				cidr = upstream.Host + "/128"
+			}
+			upstreams.To = append(upstreams.To, kubetypes.NetworkPolicyPeer{
+				IPBlock: &kubetypes.IPBlock{CIDR: cidr},
+			})
+			continue
+		}
+
+		name, namespace, ok := upstream.ServiceName()
+		if !ok {
+			continue
+		}
+
+		peer := kubetypes.NetworkPolicyPeer{
+			PodSelector: &kubetypes.LabelSelector{
+				MatchLabels: map[string]string{"srv": name},
+			},
+		}
+
+		if namespace != "" {
+			peer.NamespaceSelector = &kubetypes.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": namespace},
+			}
+		}
+
+		upstreams.To = append(upstreams.To, peer)
+	}
+
+	if len(upstreams.To) > 0 {
+		nps.Egress = append(nps.Egress, upstreams)
+	}
+
+	if s.NetworkPolicy != nil && len(s.NetworkPolicy.EgressCIDRs) > 0 {
+		cidrs := kubetypes.NetworkPolicyEgressRule{}
+
+		for _, c := range s.NetworkPolicy.EgressCIDRs {
+			cidrs.To = append(cidrs.To, kubetypes.NetworkPolicyPeer{
+				IPBlock: &kubetypes.IPBlock{
+					CIDR:   c.CIDR,
+					Except: c.Except,
+				},
+			})
+		}
+
+		nps.Egress = append(nps.Egress, cidrs)
+	}
+
+	return nps, nil
+}
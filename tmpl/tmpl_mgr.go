@@ -56,9 +56,15 @@ func (tplMgr *TemplateMgr) addTemplate(name string, text string) error {
 	return nil
 }
 
+// ExecuteAll executes all templates in order with ${...} in spec resolved, see spec.Interpolator
 func (tplMgr *TemplateMgr) ExecuteAll(writer io.Writer, s *spec.Spec) error {
+	resolved, err := s.Interpolate()
+	if err != nil {
+		return err
+	}
+
 	for _, name := range tplMgr.templateNames {
-		if err := tplMgr.execute(name, writer, s); err != nil {
+		if err := tplMgr.execute(name, writer, &resolved); err != nil {
 			return err
 		}
 	}
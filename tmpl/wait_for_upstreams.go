@@ -0,0 +1,79 @@
+package tmpl
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/helmx/kubetypes"
+	"github.com/go-courier/helmx/spec"
+)
+
+// toKubeWaitForUpstreamsContainers generates an init container for each upstream,
+// which checks tcp port by nc, http url by wget, or dns of host by nslookup for upstream without port,
+// until success or timeout.
+func toKubeWaitForUpstreamsContainers(s spec.Spec, w spec.WaitForUpstreams) ([]kubetypes.KubeContainer, error) {
+	upstreams, err := s.ResolveUpstreams()
+	if err != nil {
+		return nil, err
+	}
+
+	image := w.Image
+	if image == "" {
+		image = "busybox"
+	}
+
+	timeoutSeconds := w.TimeoutSeconds
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 60
+	}
+
+	containers := make([]kubetypes.KubeContainer, 0)
+
+	for _, u := range upstreams {
+		check := ""
+
+		switch {
+		case u.Port == 0:
+			if u.IsIP() {
+				return nil, fmt.Errorf("upstream %s: port is required to wait for ip", u)
+			}
+			check = fmt.Sprintf("nslookup %s", shellQuote(u.Host))
+		case u.Scheme == "http" || u.Scheme == "https":
+			check = fmt.Sprintf("wget -q -T 2 -O /dev/null %s", shellQuote(u.String()))
+		default:
+			check = fmt.Sprintf("nc -z -w 2 %s %d", shellQuote(u.Host), u.Port)
+		}
+
+		c := kubetypes.KubeContainer{}
+		c.Name = waitForContainerName(u)
+		c.Image = image
+		c.Command = []string{
+			"timeout", strconv.FormatInt(int64(timeoutSeconds), 10),
+			"sh", "-c",
+			fmt.Sprintf("until %s; do echo waiting for %s; sleep 2; done", check, shellQuote(u.Address())),
+		}
+
+		containers = append(containers, c)
+	}
+
+	return containers, nil
+}
+
+func waitForContainerName(u spec.Upstream) string {
+	if u.Port == 0 {
+		return spec.SafeName("wait-for-"+u.Host, spec.MaxNameLength)
+	}
+	return spec.SafeName("wait-for-"+u.Host+"-"+strconv.FormatUint(uint64(u.Port), 10), spec.MaxNameLength)
+}
+
+var reShellUnsafeChars = regexp.MustCompile(`[^\w@%+=:,./-]`)
+
+// shellQuote quotes s by single quotes for sh when s contains any unsafe char
+func shellQuote(s string) string {
+	if s != "" && !reShellUnsafeChars.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}